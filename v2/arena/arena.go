@@ -0,0 +1,192 @@
+//Package arena plays games between search configurations of gmcts
+//to compare their strength, such as to tune an exploration constant.
+//
+//Each pair of configurations plays a match of games, in which the
+//configurations swap seats every game. Each pair of consecutive games
+//is played with the same seed, so that the configurations play both
+//sides of the same game. Games of a match are played in parallel, but
+//their results are counted in the order of the games, so that matches
+//played with the same options are the same.
+//
+//Matches may be stopped early with a sequential probability ratio
+//test (SPRT), once the results are enough to tell whether the first
+//configuration is stronger than the second by a given Elo difference.
+//The SPRT is only checked after each pair of games, once both
+//configurations have played both sides of the game:
+//
+//	result, err := arena.Match(newGame, first, second, arena.Options{
+//		Games: 10000,
+//		SPRT:  &arena.SPRT{Elo0: 0, Elo1: 10, Alpha: 0.05, Beta: 0.05},
+//	})
+package arena
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+)
+
+//Config is a named search configuration playing in the arena.
+type Config struct {
+	Name   string
+	Search gmcts.SearchConfig
+}
+
+//Options configures the matches played between configurations.
+type Options struct {
+	//Games is the most games played in each match
+	Games int
+
+	//Parallel is the number of games played at the same time.
+	//A value of 0 plays as many games as there are CPUs.
+	Parallel int
+
+	//Seed seeds the games played
+	Seed int64
+
+	//SPRT stops each match early once either of its hypotheses
+	//is accepted, or is nil to play every game of each match
+	SPRT *SPRT
+}
+
+//Result is the result of a match between two configurations.
+//Results are given from the point of view of the first configuration.
+type Result struct {
+	First, Second string
+
+	Wins, Draws, Losses int
+
+	//Elo is the Elo difference between the configurations, and
+	//EloMargin is the margin of its 95% confidence interval
+	Elo, EloMargin float64
+
+	//LLR is the log-likelihood ratio of the match's SPRT, and
+	//Decision is the hypothesis it accepted, if the match had an SPRT
+	LLR      float64
+	Decision Decision
+}
+
+//Games returns the number of games played in the match.
+func (r Result) Games() int {
+	return r.Wins + r.Draws + r.Losses
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%s vs %s: +%d =%d -%d, Elo %+.1f ± %.1f",
+		r.First, r.Second, r.Wins, r.Draws, r.Losses, r.Elo, r.EloMargin)
+}
+
+//Tournament plays a match between every pair of the given configurations,
+//in the order the configurations are given, and returns the result of
+//each match. Games are started from the states returned by newGame.
+//
+//Tournament returns the results of the matches played
+//so far and the first error returned by any game.
+func Tournament(newGame func() gmcts.Game, configs []Config, opts Options) ([]Result, error) {
+	var results []Result
+	for i := range configs {
+		for j := i + 1; j < len(configs); j++ {
+			result, err := Match(newGame, configs[i], configs[j], opts)
+			results = append(results, result)
+			if err != nil {
+				return results, err
+			}
+		}
+	}
+	return results, nil
+}
+
+//Match plays a match between two configurations of a two player game,
+//and returns its result. Games are started from the states returned by
+//newGame, and are played with gmcts.SelfPlay, where the first configuration
+//plays as player 0 in even games and as player 1 in odd games.
+//
+//Match returns the result of the games played before the
+//first game that returned an error, and that error.
+func Match(newGame func() gmcts.Game, first, second Config, opts Options) (Result, error) {
+	result := Result{First: first.Name, Second: second.Name}
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = runtime.NumCPU()
+	}
+
+	//Games finish in any order, so their results are kept by game
+	//until every game before them has finished and been counted
+	var mutex sync.Mutex
+	var firstErr error
+	records := make([]*gmcts.GameRecord, opts.Games)
+	errs := make([]error, opts.Games)
+	next, counted := 0, 0
+	done := false
+
+	var wait sync.WaitGroup
+	wait.Add(parallel)
+	for w := 0; w < parallel; w++ {
+		go func() {
+			defer wait.Done()
+			for {
+				mutex.Lock()
+				if done || next >= opts.Games {
+					mutex.Unlock()
+					return
+				}
+				game := next
+				next++
+				mutex.Unlock()
+
+				seat := gmcts.Player(game % 2)
+				configs := []gmcts.SearchConfig{first.Search, second.Search}
+				if seat == 1 {
+					configs[0], configs[1] = configs[1], configs[0]
+				}
+				record, err := gmcts.SelfPlay(newGame(), configs, opts.Seed+int64(game/2))
+
+				mutex.Lock()
+				records[game], errs[game] = &record, err
+				for ; !done && counted < next && records[counted] != nil; counted++ {
+					if errs[counted] != nil {
+						firstErr = errs[counted]
+						done = true
+						break
+					}
+					result.add(records[counted].Winners, gmcts.Player(counted%2))
+					if opts.SPRT != nil && counted%2 == 1 &&
+						opts.SPRT.Decide(result.Wins, result.Draws, result.Losses) != Undecided {
+						done = true
+					}
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wait.Wait()
+
+	result.Elo, result.EloMargin = Elo(result.Wins, result.Draws, result.Losses)
+	if opts.SPRT != nil {
+		result.LLR = opts.SPRT.LLR(result.Wins, result.Draws, result.Losses)
+		result.Decision = opts.SPRT.Decide(result.Wins, result.Draws, result.Losses)
+	}
+	return result, firstErr
+}
+
+//add counts a game won by the given winners, where the
+//first configuration played as the given player
+func (r *Result) add(winners []gmcts.Player, seat gmcts.Player) {
+	won := false
+	for _, p := range winners {
+		if p == seat {
+			won = true
+		}
+	}
+
+	switch {
+	case won && len(winners) == 1:
+		r.Wins++
+	case won || len(winners) == 0:
+		r.Draws++
+	default:
+		r.Losses++
+	}
+}
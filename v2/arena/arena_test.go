@@ -0,0 +1,112 @@
+package arena
+
+import (
+	"math"
+	"testing"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+	"github.com/0xhexnumbers/gmcts/v2/internal/testgames"
+)
+
+//newNimGame returns a game of nim where players take 1 to 3 stones from 21
+func newNimGame() gmcts.Game {
+	return testgames.Nim{Stones: 21, MaxTake: 3}
+}
+
+var (
+	strong = Config{Name: "strong", Search: gmcts.SearchConfig{Rounds: 1000}}
+	weak   = Config{Name: "weak", Search: gmcts.SearchConfig{Rounds: 5}}
+)
+
+func TestMatch(t *testing.T) {
+	result, err := Match(newNimGame, strong, weak, Options{Games: 40, Parallel: 4})
+	if err != nil {
+		t.Errorf("Match returned an error: %s", err)
+		t.FailNow()
+	}
+	if result.Games() != 40 || result.Wins <= result.Losses || result.Elo <= 0 {
+		t.Errorf("Strong configuration scored %s: wanted 40 games, more wins than losses, and Elo > 0", result)
+		t.FailNow()
+	}
+
+	//Matches with the same seed should be the same
+	again, _ := Match(newNimGame, strong, weak, Options{Games: 40, Parallel: 1})
+	if again != result {
+		t.Errorf("Match with the same seed scored %s: wanted %s", again, result)
+		t.FailNow()
+	}
+}
+
+func TestMatchSPRT(t *testing.T) {
+	sprt := &SPRT{Elo0: 0, Elo1: 50, Alpha: 0.05, Beta: 0.05}
+	result, _ := Match(newNimGame, strong, weak, Options{Games: 1000, Parallel: 4, SPRT: sprt})
+	if result.Decision != AcceptH1 || result.Games() >= 1000 || result.Games()%2 != 0 {
+		t.Errorf("Strong configuration scored %s with LLR %f: wanted H1 accepted after a pair of games before 1000 games", result, result.LLR)
+		t.FailNow()
+	}
+
+	//Matches stopped early should not depend on the order games finish in
+	for _, parallel := range []int{1, 3, 8} {
+		again, _ := Match(newNimGame, strong, weak, Options{Games: 1000, Parallel: parallel, SPRT: sprt})
+		if again != result {
+			t.Errorf("Match playing %d games in parallel scored %s: wanted %s", parallel, again, result)
+			t.FailNow()
+		}
+	}
+}
+
+func TestTournament(t *testing.T) {
+	medium := Config{Name: "medium", Search: gmcts.SearchConfig{Rounds: 100}}
+	results, err := Tournament(newNimGame, []Config{strong, medium, weak}, Options{Games: 4})
+	if err != nil || len(results) != 3 {
+		t.Errorf("Tournament between 3 configurations returned %d results and error %v: wanted 3 results", len(results), err)
+		t.FailNow()
+	}
+	if results[2].First != "medium" || results[2].Second != "weak" {
+		t.Errorf("Last match of the tournament was %s vs %s: wanted medium vs weak", results[2].First, results[2].Second)
+		t.FailNow()
+	}
+}
+
+func TestElo(t *testing.T) {
+	tests := []struct {
+		wins, draws, losses int
+		elo                 float64
+	}{
+		{5, 0, 5, 0},
+		{0, 10, 0, 0},
+		{3, 0, 1, 400 * math.Log10(3)},
+		{1, 0, 3, -400 * math.Log10(3)},
+	}
+	for _, test := range tests {
+		elo, margin := Elo(test.wins, test.draws, test.losses)
+		if math.Abs(elo-test.elo) > 1e-9 || margin < 0 {
+			t.Errorf("Elo(%d, %d, %d) returned %f ± %f: wanted %f", test.wins, test.draws, test.losses, elo, margin, test.elo)
+			t.FailNow()
+		}
+	}
+
+	//More games should narrow the confidence interval
+	_, few := Elo(6, 2, 4)
+	_, many := Elo(60, 20, 40)
+	if many >= few {
+		t.Errorf("Elo margin was %f after 120 games and %f after 12 games: wanted it to narrow", many, few)
+		t.FailNow()
+	}
+}
+
+func TestSPRT(t *testing.T) {
+	sprt := SPRT{Elo0: 0, Elo1: 10, Alpha: 0.05, Beta: 0.05}
+	if d := sprt.Decide(0, 0, 0); d != Undecided {
+		t.Errorf("SPRT decided %d with no games: wanted %d", d, Undecided)
+		t.FailNow()
+	}
+	if d := sprt.Decide(6000, 0, 4000); d != AcceptH1 {
+		t.Errorf("SPRT decided %d with a score of 60%%: wanted %d", d, AcceptH1)
+		t.FailNow()
+	}
+	if d := sprt.Decide(4000, 0, 6000); d != AcceptH0 {
+		t.Errorf("SPRT decided %d with a score of 40%%: wanted %d", d, AcceptH0)
+		t.FailNow()
+	}
+}
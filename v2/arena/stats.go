@@ -0,0 +1,120 @@
+package arena
+
+import "math"
+
+//Decision is the hypothesis accepted by an SPRT.
+type Decision int
+
+const (
+	//Undecided means neither hypothesis has been accepted yet
+	Undecided Decision = iota
+
+	//AcceptH0 means the Elo difference is likely at most Elo0
+	AcceptH0
+
+	//AcceptH1 means the Elo difference is likely at least Elo1
+	AcceptH1
+)
+
+//SPRT configures a sequential probability ratio test between two
+//hypotheses on the Elo difference between two configurations:
+//H0, that the difference is Elo0, and H1, that it is Elo1.
+type SPRT struct {
+	Elo0, Elo1 float64
+
+	//Alpha is the probability of accepting H1 when H0 is true,
+	//and Beta is the probability of accepting H0 when H1 is true
+	Alpha, Beta float64
+}
+
+//Bounds returns the log-likelihood ratios below
+//which H0 is accepted, and above which H1 is accepted.
+func (s SPRT) Bounds() (float64, float64) {
+	return math.Log(s.Beta / (1 - s.Alpha)), math.Log((1 - s.Beta) / s.Alpha)
+}
+
+//LLR returns the log-likelihood ratio of H1 over H0 given the
+//results of the games played, using the normal approximation
+//of the distribution of the score of each game.
+//
+//While every game has had the same result, the variance of the
+//score is taken to be that of games without draws, scoring halfway
+//between the scores expected under each hypothesis.
+func (s SPRT) LLR(wins, draws, losses int) float64 {
+	games := float64(wins + draws + losses)
+	if games == 0 {
+		return 0
+	}
+
+	s0, s1 := expectedScore(s.Elo0), expectedScore(s.Elo1)
+	score, variance := scoreVariance(wins, draws, losses)
+	if variance == 0 {
+		mid := (s0 + s1) / 2
+		variance = mid * (1 - mid)
+	}
+	return games * (s1 - s0) * (2*score - s0 - s1) / (2 * variance)
+}
+
+//Decide returns the hypothesis accepted given the results of the games played.
+func (s SPRT) Decide(wins, draws, losses int) Decision {
+	lower, upper := s.Bounds()
+	llr := s.LLR(wins, draws, losses)
+	if llr >= upper {
+		return AcceptH1
+	} else if llr <= lower {
+		return AcceptH0
+	}
+	return Undecided
+}
+
+//Elo returns the Elo difference given the results of the games played,
+//along with the margin of its 95% confidence interval. The difference
+//and its margin are infinite if every game was won or every game was lost.
+func Elo(wins, draws, losses int) (float64, float64) {
+	games := float64(wins + draws + losses)
+	if games == 0 {
+		return 0, math.Inf(1)
+	}
+
+	score, variance := scoreVariance(wins, draws, losses)
+	elo := eloDifference(score)
+	if math.IsInf(elo, 0) {
+		return elo, math.Inf(1)
+	}
+
+	margin := 1.959964 * math.Sqrt(variance/games)
+	low, high := eloDifference(score-margin), eloDifference(score+margin)
+	return elo, (high - low) / 2
+}
+
+//scoreVariance returns the mean and variance of the score of each
+//game, where a win is worth 1, a draw 0.5, and a loss 0
+func scoreVariance(wins, draws, losses int) (float64, float64) {
+	games := float64(wins + draws + losses)
+	if games == 0 {
+		return 0, 0
+	}
+
+	score := (float64(wins) + float64(draws)/2) / games
+	variance := (float64(wins)*(1-score)*(1-score) +
+		float64(draws)*(0.5-score)*(0.5-score) +
+		float64(losses)*score*score) / games
+	return score, variance
+}
+
+//expectedScore returns the expected score of a game
+//against an opponent rated the given Elo lower
+func expectedScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+//eloDifference returns the Elo difference expected to give the
+//score, which is the inverse of expectedScore
+func eloDifference(score float64) float64 {
+	if score <= 0 {
+		return math.Inf(-1)
+	} else if score >= 1 {
+		return math.Inf(1)
+	}
+	return -400 * math.Log10(1/score-1)
+}
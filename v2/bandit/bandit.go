@@ -0,0 +1,151 @@
+//Package bandit implements policies for multi-armed bandit problems,
+//where each round one of several arms is pulled to receive a reward,
+//and the policy balances pulling the arms with the best rewards so far
+//against pulling the arms it knows little about.
+//
+//The index policies, such as UCB1, are also used by gmcts trees to
+//select the children of the nodes they search through.
+//
+//Rewards are assumed to be between 0 and 1.
+package bandit
+
+import (
+	"math/rand"
+	"sync"
+)
+
+//Arm holds the statistics of the rewards received from an arm.
+type Arm struct {
+	//Pulls is the number of times the arm was pulled
+	Pulls float64
+
+	//Mean is the average reward received from the arm
+	Mean float64
+
+	//Variance is the variance of the rewards received from the arm
+	Variance float64
+
+	//Prior is the probability of the arm being the best arm
+	//before it is pulled, which is only used by PUCT
+	Prior float64
+}
+
+//Update adds a reward received from pulling the arm to its statistics.
+func (a *Arm) Update(reward float64) {
+	a.Pulls++
+	delta := reward - a.Mean
+	a.Mean += delta / a.Pulls
+	a.Variance += (delta*(reward-a.Mean) - a.Variance) / a.Pulls
+}
+
+//Policy selects the next arm to pull.
+type Policy interface {
+	//Select returns the index of the arm to pull
+	Select(arms []Arm, randSource *rand.Rand) int
+}
+
+//Updater is an optional interface a Policy may implement to keep
+//state of its own, which is updated with every reward received.
+type Updater interface {
+	//Update is called with the reward received from the given arm,
+	//before the reward is added to the arm's statistics
+	Update(arms []Arm, arm int, reward float64)
+}
+
+//Scorer is a policy scoring each arm on its own statistics,
+//selecting the arm with the highest score.
+type Scorer interface {
+	//Score returns the score of the given arm
+	//after pulls pulls of every arm combined
+	Score(arm Arm, pulls float64, randSource *rand.Rand) float64
+}
+
+//SelectByScore returns the index of the first arm that has never been
+//pulled, or the arm with the highest score if every arm has been pulled.
+func SelectByScore(s Scorer, arms []Arm, randSource *rand.Rand) int {
+	var pulls float64
+	for i, arm := range arms {
+		if arm.Pulls == 0 {
+			return i
+		}
+		pulls += arm.Pulls
+	}
+
+	var best int
+	var bestScore float64
+	for i, arm := range arms {
+		if score := s.Score(arm, pulls, randSource); i == 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+//Bandit is a set of arms pulled according to a policy.
+//It is safe for concurrent use.
+type Bandit struct {
+	arms       []Arm
+	policy     Policy
+	randSource *rand.Rand
+	mutex      sync.Mutex
+}
+
+//New returns a bandit with the given number of arms, none of which
+//have been pulled yet. The seed seeds policies that select randomly.
+func New(arms int, policy Policy, seed int64) *Bandit {
+	return &Bandit{
+		arms:       make([]Arm, arms),
+		policy:     policy,
+		randSource: rand.New(rand.NewSource(seed)),
+	}
+}
+
+//NewWithPriors returns a bandit with an arm for each of the
+//given prior probabilities, none of which have been pulled yet.
+func NewWithPriors(priors []float64, policy Policy, seed int64) *Bandit {
+	b := New(len(priors), policy, seed)
+	for i, prior := range priors {
+		b.arms[i].Prior = prior
+	}
+	return b
+}
+
+//Select returns the index of the next arm to pull.
+func (b *Bandit) Select() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.policy.Select(b.arms, b.randSource)
+}
+
+//Update adds the reward received from pulling the given arm.
+func (b *Bandit) Update(arm int, reward float64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if updater, ok := b.policy.(Updater); ok {
+		updater.Update(b.arms, arm, reward)
+	}
+	b.arms[arm].Update(reward)
+}
+
+//Arms returns a copy of the statistics of each arm.
+func (b *Bandit) Arms() []Arm {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	arms := make([]Arm, len(b.arms))
+	copy(arms, b.arms)
+	return arms
+}
+
+//Best returns the index of the arm with the most pulls, which
+//is the arm the policy has found to have the best rewards.
+func (b *Bandit) Best() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var best int
+	for i, arm := range b.arms {
+		if arm.Pulls > b.arms[best].Pulls {
+			best = i
+		}
+	}
+	return best
+}
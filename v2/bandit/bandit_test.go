@@ -0,0 +1,115 @@
+package bandit
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestArmUpdate(t *testing.T) {
+	rewards := []float64{1, 0, 0.5, 1, 1}
+	var arm Arm
+	for _, reward := range rewards {
+		arm.Update(reward)
+	}
+
+	var mean, variance float64
+	for _, reward := range rewards {
+		mean += reward / float64(len(rewards))
+	}
+	for _, reward := range rewards {
+		variance += (reward - mean) * (reward - mean) / float64(len(rewards))
+	}
+	if arm.Pulls != 5 || math.Abs(arm.Mean-mean) > 1e-9 || math.Abs(arm.Variance-variance) > 1e-9 {
+		t.Errorf("Arm has statistics %+v: wanted 5 pulls, mean %f and variance %f", arm, mean, variance)
+		t.FailNow()
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	policies := []Policy{
+		UCB1{C: math.Sqrt2},
+		UCB1Tuned{},
+		UCBV{Zeta: 1.2, C: 1},
+		Thompson{},
+		PUCT{C: 1.5},
+		NewEXP3(0.1),
+	}
+	odds := []float64{0.2, 0.5, 0.8}
+	priors := []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}
+	for _, policy := range policies {
+		b := NewWithPriors(priors, policy, 0)
+		rewards := rand.New(rand.NewSource(1))
+		for i := 0; i < 5000; i++ {
+			arm := b.Select()
+			var reward float64
+			if rewards.Float64() < odds[arm] {
+				reward = 1
+			}
+			b.Update(arm, reward)
+		}
+
+		if best := b.Best(); best != 2 {
+			t.Errorf("Bandit with policy %T found arm %d to be the best, with arms %+v: wanted 2", policy, best, b.Arms())
+			t.FailNow()
+		}
+	}
+}
+
+func TestSelectByScore(t *testing.T) {
+	arms := []Arm{{Pulls: 1, Mean: 1}, {}, {Pulls: 1}}
+	if arm := SelectByScore(UCB1{C: 1}, arms, nil); arm != 1 {
+		t.Errorf("Selected arm %d with arm 1 never pulled: wanted 1", arm)
+		t.FailNow()
+	}
+
+	arms[1] = Arm{Pulls: 1, Mean: 0.5}
+	if arm := SelectByScore(UCB1{C: 1}, arms, nil); arm != 0 {
+		t.Errorf("Selected arm %d with arm 0 having the best mean: wanted 0", arm)
+		t.FailNow()
+	}
+}
+
+func TestEXP3Probabilities(t *testing.T) {
+	exp3 := NewEXP3(0.1)
+	arms := make([]Arm, 4)
+	for i := 0; i < 1000; i++ {
+		exp3.Update(arms, 0, 1)
+	}
+
+	var total float64
+	probabilities := exp3.Probabilities(arms)
+	for _, p := range probabilities {
+		if p < 0.1/4 {
+			t.Errorf("EXP3 has probability %f of selecting an arm: wanted >= %f", p, 0.1/4)
+			t.FailNow()
+		}
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("EXP3 probabilities sum to %f: wanted 1", total)
+		t.FailNow()
+	}
+	if probabilities[0] < 0.9 {
+		t.Errorf("EXP3 has probability %f of selecting the only rewarded arm: wanted >= 0.9", probabilities[0])
+		t.FailNow()
+	}
+}
+
+func TestDirichlet(t *testing.T) {
+	randSource := rand.New(rand.NewSource(0))
+	for _, alpha := range []float64{0.03, 0.3, 3} {
+		var total float64
+		for _, sample := range Dirichlet(alpha, 10, randSource) {
+			if sample < 0 {
+				t.Errorf("Dirichlet with alpha %f sampled %f: wanted >= 0", alpha, sample)
+				t.FailNow()
+			}
+			total += sample
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("Dirichlet with alpha %f sampled a total of %f: wanted 1", alpha, total)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package bandit
+
+import (
+	"math"
+	"math/rand"
+)
+
+//UCB1 scores arms with the UCB1 formula, where
+//C is the exploration constant.
+type UCB1 struct {
+	C float64
+}
+
+//Score implements Scorer.
+func (u UCB1) Score(arm Arm, pulls float64, randSource *rand.Rand) float64 {
+	return arm.Mean + u.C*math.Sqrt(math.Log(pulls)/arm.Pulls)
+}
+
+//Select implements Policy.
+func (u UCB1) Select(arms []Arm, randSource *rand.Rand) int {
+	return SelectByScore(u, arms, randSource)
+}
+
+//UCB1Tuned scores arms with the UCB1-Tuned formula, which
+//bounds the exploration of each arm by the variance of its rewards.
+type UCB1Tuned struct{}
+
+//Score implements Scorer.
+func (UCB1Tuned) Score(arm Arm, pulls float64, randSource *rand.Rand) float64 {
+	logPulls := math.Log(pulls)
+	bound := arm.Variance + math.Sqrt(2*logPulls/arm.Pulls)
+	if bound > 0.25 {
+		bound = 0.25
+	}
+	return arm.Mean + math.Sqrt(logPulls/arm.Pulls*bound)
+}
+
+//Select implements Policy.
+func (u UCB1Tuned) Select(arms []Arm, randSource *rand.Rand) int {
+	return SelectByScore(u, arms, randSource)
+}
+
+//UCBV scores arms with the UCB-V formula, which explores arms
+//in proportion to the variance of their rewards. Zeta scales the
+//exploration of every arm, and C scales the exploration of arms
+//with little variance. Zeta is usually 1.2 and C is usually 1.
+type UCBV struct {
+	Zeta, C float64
+}
+
+//Score implements Scorer.
+func (u UCBV) Score(arm Arm, pulls float64, randSource *rand.Rand) float64 {
+	exploration := u.Zeta * math.Log(pulls)
+	return arm.Mean + math.Sqrt(2*arm.Variance*exploration/arm.Pulls) + 3*u.C*exploration/arm.Pulls
+}
+
+//Select implements Policy.
+func (u UCBV) Select(arms []Arm, randSource *rand.Rand) int {
+	return SelectByScore(u, arms, randSource)
+}
+
+//PUCT scores arms with the PUCT formula, which explores arms in
+//proportion to their prior probability, where C is the exploration
+//constant. PUCT is suited to arms with a good prior, such as the
+//actions of a game given by a trained policy.
+type PUCT struct {
+	C float64
+}
+
+//Score implements Scorer.
+func (u PUCT) Score(arm Arm, pulls float64, randSource *rand.Rand) float64 {
+	return arm.Mean + u.C*arm.Prior*math.Sqrt(pulls)/(1+arm.Pulls)
+}
+
+//Select implements Policy. Unlike the other index policies,
+//arms that have never been pulled are scored by their prior as well.
+func (u PUCT) Select(arms []Arm, randSource *rand.Rand) int {
+	var pulls float64
+	for _, arm := range arms {
+		pulls += arm.Pulls
+	}
+
+	var best int
+	var bestScore float64
+	for i, arm := range arms {
+		if score := u.Score(arm, pulls, randSource); i == 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+//Thompson scores arms with Thompson sampling, sampling each arm's
+//reward from a Beta posterior of its successes and failures.
+type Thompson struct{}
+
+//Score implements Scorer.
+func (Thompson) Score(arm Arm, pulls float64, randSource *rand.Rand) float64 {
+	successes := arm.Mean * arm.Pulls
+	return sampleBeta(1+successes, 1+arm.Pulls-successes, randSource)
+}
+
+//Select implements Policy. Unlike the other policies,
+//arms that have never been pulled are sampled as well.
+func (t Thompson) Select(arms []Arm, randSource *rand.Rand) int {
+	var best int
+	var bestScore float64
+	for i, arm := range arms {
+		if score := t.Score(arm, 0, randSource); i == 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+//EXP3 selects arms randomly, with a probability that grows exponentially
+//with the rewards received from them, mixed with a uniform probability
+//of Gamma. Unlike the other policies, EXP3 makes no assumption that the
+//rewards of an arm are drawn from a fixed distribution, which suits
+//rewards chosen by an adversary.
+//
+//EXP3 keeps an estimate of each arm's total reward,
+//so each EXP3 must only be used by a single Bandit.
+type EXP3 struct {
+	Gamma float64
+
+	estimates []float64
+}
+
+//NewEXP3 returns an EXP3 policy with the given mixing probability.
+func NewEXP3(gamma float64) *EXP3 {
+	return &EXP3{Gamma: gamma}
+}
+
+//Probabilities returns the probability of selecting each of the given arms.
+func (e *EXP3) Probabilities(arms []Arm) []float64 {
+	for len(e.estimates) < len(arms) {
+		e.estimates = append(e.estimates, 0)
+	}
+
+	//Subtract the largest estimate to keep the weights from overflowing
+	k := float64(len(arms))
+	maxEstimate := math.Inf(-1)
+	for _, estimate := range e.estimates[:len(arms)] {
+		maxEstimate = math.Max(maxEstimate, estimate)
+	}
+	probabilities := make([]float64, len(arms))
+	var totalWeight float64
+	for i, estimate := range e.estimates[:len(arms)] {
+		probabilities[i] = math.Exp(e.Gamma * (estimate - maxEstimate) / k)
+		totalWeight += probabilities[i]
+	}
+	for i := range probabilities {
+		probabilities[i] = (1-e.Gamma)*probabilities[i]/totalWeight + e.Gamma/k
+	}
+	return probabilities
+}
+
+//Select implements Policy.
+func (e *EXP3) Select(arms []Arm, randSource *rand.Rand) int {
+	probabilities := e.Probabilities(arms)
+	r := randSource.Float64()
+	for i, p := range probabilities {
+		if r < p {
+			return i
+		}
+		r -= p
+	}
+	return len(arms) - 1
+}
+
+//Update implements Updater.
+func (e *EXP3) Update(arms []Arm, arm int, reward float64) {
+	probabilities := e.Probabilities(arms)
+	e.estimates[arm] += reward / probabilities[arm]
+}
+
+//Dirichlet samples a symmetric Dirichlet distribution with the given
+//concentration over the given number of arms. The samples sum to 1,
+//and are spread more evenly between the arms as alpha grows. They can
+//be mixed into the priors of arms to explore arms the priors neglect.
+func Dirichlet(alpha float64, arms int, randSource *rand.Rand) []float64 {
+	samples := make([]float64, arms)
+	var total float64
+	for i := range samples {
+		samples[i] = sampleGamma(alpha, randSource)
+		total += samples[i]
+	}
+	for i := range samples {
+		samples[i] /= total
+	}
+	return samples
+}
+
+//sampleBeta samples a Beta(a, b) distribution
+func sampleBeta(a, b float64, randSource *rand.Rand) float64 {
+	x := sampleGamma(a, randSource)
+	y := sampleGamma(b, randSource)
+	return x / (x + y)
+}
+
+//sampleGamma samples a Gamma(shape, 1) distribution using the method
+//of Marsaglia and Tsang. Shapes below 1 are sampled from a shape
+//above 1, scaled by a uniform sample.
+func sampleGamma(shape float64, randSource *rand.Rand) float64 {
+	if shape < 1 {
+		u := randSource.Float64()
+		return sampleGamma(shape+1, randSource) * math.Pow(u, 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := randSource.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := randSource.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
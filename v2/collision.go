@@ -0,0 +1,66 @@
+package gmcts
+
+import "fmt"
+
+func (e *CollisionError) Error() string {
+	return fmt.Sprintf("gmcts: distinct states collide on hash %v", e.Hash)
+}
+
+//cached returns the node cached under the given key for the given
+//state, or nil if the state has no node yet. Unless collisions are
+//unchecked, the cached nodes' states are compared to the given state.
+func (t *Tree) cached(key gameHash, state Game) *node {
+	cachedNode := t.gameStates[key]
+	if cachedNode == nil || t.collisions == CollisionsUnchecked {
+		return cachedNode
+	}
+
+	if state.(Equaler).Equal(cachedNode.state.Game) {
+		return cachedNode
+	}
+	for _, collided := range t.collided[key] {
+		if state.(Equaler).Equal(collided.state.Game) {
+			return collided
+		}
+	}
+
+	t.collisionCount++
+	if t.collisionHandler != nil {
+		t.collisionErrors = append(t.collisionErrors, &CollisionError{key.hash, cachedNode.state.Game, state})
+	}
+	return nil
+}
+
+//cache saves a node for reuse under the given key. If the key is
+//already used by a distinct state, the node is kept with the other
+//nodes that collided on the key.
+func (t *Tree) cache(key gameHash, n *node) {
+	if _, used := t.gameStates[key]; used {
+		t.collided[key] = append(t.collided[key], n)
+		return
+	}
+	t.gameStates[key] = n
+}
+
+//SetCollisionHandler registers a handler that is reported each
+//distinct state found with the same hash as a state already in the
+//tree, if the tree checks for collisions. A nil handler removes any
+//registered handler.
+//
+//The handler is invoked from the goroutine searching the tree once
+//the round finding the collision is over and the tree is unlocked.
+func (t *Tree) SetCollisionHandler(handler func(*CollisionError)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.collisionHandler = handler
+	t.collisionErrors = nil
+}
+
+//Collisions returns the number of distinct states this tree has found
+//with the same hash as a state already in the tree. It is always 0 if
+//collisions are unchecked.
+func (t *Tree) Collisions() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.collisionCount
+}
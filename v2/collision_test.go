@@ -0,0 +1,103 @@
+package gmcts
+
+import "testing"
+
+//collidingGame is a tic-tac-toe game whose hash only counts the pieces
+//on the board, so that distinct states on the same turn collide
+type collidingGame struct {
+	immutableBoardGame
+}
+
+func (g collidingGame) Hash() interface{} {
+	return len(g.game.moves)
+}
+
+func (g collidingGame) ApplyAction(i int) (Game, error) {
+	next, err := g.immutableBoardGame.ApplyAction(i)
+	return collidingGame{next.(immutableBoardGame)}, err
+}
+
+func (g collidingGame) Equal(other Game) bool {
+	return g.game.board == other.(collidingGame).game.board
+}
+
+func TestCollisionModes(t *testing.T) {
+	exactTree := NewMCTS(immutableBoardGame{newBoardGame()}).SpawnTree()
+	exactTree.SearchRounds(10000)
+
+	//Unchecked collisions share nodes between distinct states
+	mcts := NewMCTS(collidingGame{immutableBoardGame{newBoardGame()}})
+	tree := mcts.SpawnTree()
+	tree.SearchRounds(1000)
+	if tree.Collisions() != 0 || tree.Nodes() > 10 {
+		t.Errorf("Tree with unchecked collisions has %d nodes and found %d collisions: wanted <= 10 and 0", tree.Nodes(), tree.Collisions())
+		t.FailNow()
+	}
+
+	//Separated collisions should search the same tree as exact hashes
+	mcts.SetCollisionMode(CollisionsSeparated)
+	mcts.SetSeed(0)
+	tree = mcts.SpawnTree()
+	tree.SearchRounds(10000)
+	if info, exactInfo := tree.Info(), exactTree.Info(); info != exactInfo {
+		t.Errorf("Tree with separated collisions searched %+v: wanted %+v", info, exactInfo)
+		t.FailNow()
+	}
+	if tree.Collisions() == 0 {
+		t.Errorf("Tree with separated collisions found 0 collisions: wanted > 0")
+		t.FailNow()
+	}
+
+	//Collisions should still be separated after rerooting
+	next, _ := mcts.init.ApplyAction(4)
+	next, _ = next.ApplyAction(0)
+	if !tree.Reroot(next) {
+		t.Errorf("Tree with separated collisions could not reroot to a searched state")
+		t.FailNow()
+	}
+	if !next.(Equaler).Equal(tree.current.state.Game) {
+		t.Errorf("Tree with separated collisions rerooted to a distinct state with the same hash")
+		t.FailNow()
+	}
+	tree.SearchRounds(1000)
+	if bestAction := tree.bestAction(); bestAction < 0 {
+		t.Errorf("Tree with separated collisions could not search after rerooting")
+		t.FailNow()
+	}
+
+}
+
+func TestCollisionHandler(t *testing.T) {
+	mcts := NewMCTS(collidingGame{immutableBoardGame{newBoardGame()}})
+	mcts.SetCollisionMode(CollisionsSeparated)
+	tree := mcts.SpawnTree()
+
+	//The handler is invoked once the tree is unlocked,
+	//so it may query the tree it was registered on
+	var collisions []*CollisionError
+	tree.SetCollisionHandler(func(err *CollisionError) {
+		tree.Rounds()
+		collisions = append(collisions, err)
+	})
+	tree.SearchRounds(1000)
+
+	if len(collisions) == 0 || len(collisions) != tree.Collisions() {
+		t.Errorf("Handler was reported %d collisions for a tree finding %d: wanted the same number > 0", len(collisions), tree.Collisions())
+		t.FailNow()
+	}
+	for _, err := range collisions {
+		if err.Cached.(Equaler).Equal(err.State) || err.Hash != err.State.Hash() {
+			t.Errorf("Handler was reported equal states or the wrong hash: %+v", err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestCollisionModeWithoutEqual(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Setting a collision mode on a game without Equal did not panic")
+		}
+	}()
+	NewMCTS(newBoardGame()).SetCollisionMode(CollisionsSeparated)
+}
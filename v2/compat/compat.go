@@ -0,0 +1,181 @@
+//Package compat adapts games between the Action based Game interface
+//of gmcts v1 and the index based Game interface of gmcts v2, letting
+//a game written for either version be searched by the other.
+//
+//compat is its own module, so that only the programs
+//importing it depend on both versions of gmcts.
+package compat
+
+import (
+	"reflect"
+	"sync"
+
+	v1 "github.com/0xhexnumbers/gmcts"
+	"github.com/0xhexnumbers/gmcts/v2"
+)
+
+//FromV1 adapts a v1 game to be searched by gmcts v2.
+//
+//The actions of each state are the ones returned by GetActions, in the
+//same order, and the hash of each state is the v1 game state itself.
+//
+//Like v1's NewMCTS, FromV1 panics if either the game or the actions
+//of the given state are not comparable.
+func FromV1(initial v1.Game) gmcts.Game {
+	//Check if Game type if comparable
+	if !reflect.TypeOf(initial).Comparable() {
+		panic("gmcts/compat: game type is not comparable")
+	}
+
+	//Check if Action type is comparable
+	//We only need to check the actions that can affect the initial gamestate
+	//as those are the only actions that need to be compared.
+	actions := initial.GetActions()
+	for i := range actions {
+		if !reflect.TypeOf(actions[i]).Comparable() {
+			panic("gmcts/compat: action type is not comparable")
+		}
+	}
+
+	return v1Game{initial, actions}
+}
+
+//v1Game is a v1 game adapted to the v2 Game interface
+type v1Game struct {
+	game    v1.Game
+	actions []v1.Action
+}
+
+func (g v1Game) Len() int {
+	return len(g.actions)
+}
+
+func (g v1Game) ApplyAction(i int) (gmcts.Game, error) {
+	game, err := g.game.ApplyAction(g.actions[i])
+	if err != nil {
+		return nil, err
+	}
+	return v1Game{game, game.GetActions()}, nil
+}
+
+func (g v1Game) Hash() interface{} {
+	return g.game
+}
+
+func (g v1Game) Player() gmcts.Player {
+	return gmcts.Player(g.game.Player())
+}
+
+func (g v1Game) IsTerminal() bool {
+	return g.game.IsTerminal()
+}
+
+func (g v1Game) Winners() []gmcts.Player {
+	v1Winners := g.game.Winners()
+	winners := make([]gmcts.Player, len(v1Winners))
+	for i, p := range v1Winners {
+		winners[i] = gmcts.Player(p)
+	}
+	return winners
+}
+
+//ToV1 adapts a v2 game to be searched by gmcts v1.
+//
+//The actions of each state are the action indices from 0 to Len()-1.
+//States are compared using their Hash, so the adapted states are always
+//comparable as required by v1, but searching them will panic if any
+//state's Hash returns a noncomparable value.
+//
+//Every state reached from the adapted initial state is kept for as long
+//as any of the adapted states is referenced, so the memory used grows
+//with every distinct state searched. Adapt each new position with its own
+//call to ToV1, rather than searching from states adapted long before, to
+//let the states of earlier searches be released.
+func ToV1(initial gmcts.Game) v1.Game {
+	s := &states{games: make(map[interface{}]gmcts.Game)}
+	return s.add(initial)
+}
+
+//states holds every v2 game state adapted by ToV1, so that
+//the adapted states only need to hold their hash.
+type states struct {
+	games map[interface{}]gmcts.Game
+	mutex sync.RWMutex
+}
+
+//add saves a game state and returns its v1 adaptation
+func (s *states) add(game gmcts.Game) v2Game {
+	hash := game.Hash()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.games[hash]; !ok {
+		s.games[hash] = game
+	}
+	return v2Game{hash, s}
+}
+
+func (s *states) get(hash interface{}) gmcts.Game {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.games[hash]
+}
+
+//v2Game is a v2 game adapted to the v1 Game interface. Adapted states
+//from the same call to ToV1 share the same states, so they are only
+//compared by their hash.
+type v2Game struct {
+	hash   interface{}
+	states *states
+}
+
+func (g v2Game) GetActions() []v1.Action {
+	actions := make([]v1.Action, g.states.get(g.hash).Len())
+	for i := range actions {
+		actions[i] = i
+	}
+	return actions
+}
+
+func (g v2Game) ApplyAction(a v1.Action) (v1.Game, error) {
+	game, err := g.states.get(g.hash).ApplyAction(a.(int))
+	if err != nil {
+		return nil, err
+	}
+	return g.states.add(game), nil
+}
+
+func (g v2Game) Player() v1.Player {
+	return v1.Player(g.states.get(g.hash).Player())
+}
+
+func (g v2Game) IsTerminal() bool {
+	return g.states.get(g.hash).IsTerminal()
+}
+
+func (g v2Game) Winners() []v1.Player {
+	v2Winners := g.states.get(g.hash).Winners()
+	winners := make([]v1.Player, len(v2Winners))
+	for i, p := range v2Winners {
+		winners[i] = v1.Player(p)
+	}
+	return winners
+}
+
+//V2 returns the v2 game state a v1 game state returned by ToV1 adapts.
+//It returns nil if the given state was not returned by ToV1.
+func V2(game v1.Game) gmcts.Game {
+	if g, ok := game.(v2Game); ok {
+		return g.states.get(g.hash)
+	}
+	return nil
+}
+
+//V1 returns the v1 game state a v2 game state returned by FromV1 adapts.
+//It returns nil if the given state was not returned by FromV1.
+func V1(game gmcts.Game) v1.Game {
+	if g, ok := game.(v1Game); ok {
+		return g.game
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package compat
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "github.com/0xhexnumbers/gmcts"
+	"github.com/0xhexnumbers/gmcts/v2"
+	"github.com/0xhexnumbers/gmcts/v2/internal/testgames"
+	tictactoe "github.com/0xhexnumbers/go-tic-tac-toe"
+)
+
+//v1TTTGame is a tic-tac-toe game implementing v1's Game
+type v1TTTGame struct {
+	game tictactoe.Game
+}
+
+func (g v1TTTGame) GetActions() []v1.Action {
+	moves := g.game.GetActions()
+	actions := make([]v1.Action, len(moves))
+	for i, m := range moves {
+		actions[i] = m
+	}
+	return actions
+}
+
+func (g v1TTTGame) ApplyAction(a v1.Action) (v1.Game, error) {
+	game, err := g.game.ApplyAction(a.(tictactoe.Move))
+	return v1TTTGame{game}, err
+}
+
+func (g v1TTTGame) Player() v1.Player {
+	return v1.Player(testgames.PlayerID(g.game.Player()))
+}
+
+func (g v1TTTGame) IsTerminal() bool {
+	return g.game.IsTerminal()
+}
+
+func (g v1TTTGame) Winners() []v1.Player {
+	var players []v1.Player
+	for _, p := range testgames.Winners(g.game) {
+		players = append(players, v1.Player(p))
+	}
+	return players
+}
+
+func TestFromV1(t *testing.T) {
+	game := FromV1(v1TTTGame{tictactoe.NewGame()})
+
+	mcts := gmcts.NewMCTS(game)
+	tree := mcts.SpawnTree()
+	tree.SearchRounds(10000)
+	mcts.AddTree(tree)
+
+	bestAction, err := mcts.BestAction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	move := V1(game).GetActions()[bestAction]
+	if fmt.Sprintf("%v", move) != "{1 1}" {
+		t.Errorf("v1 game searched by v2 did not take the middle spot: %v", move)
+	}
+}
+
+func TestToV1(t *testing.T) {
+	game := ToV1(testgames.NewTicTacToe())
+
+	mcts := v1.NewMCTS(game)
+	tree := mcts.SpawnTree()
+	tree.SearchRounds(10000)
+	mcts.AddTree(tree)
+
+	move := V2(game).(testgames.TicTacToe).Actions[mcts.BestAction().(int)]
+	if fmt.Sprintf("%v", move) != "{1 1}" {
+		t.Errorf("v2 game searched by v1 did not take the middle spot: %v", move)
+	}
+}
+
+//nonComparableState is a v1 game that is not comparable
+type nonComparableState struct {
+	v1TTTGame
+	_ []int
+}
+
+func TestFromV1NonComparable(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("FromV1 did not panic on a noncomparable game")
+		}
+	}()
+	FromV1(nonComparableState{v1TTTGame{tictactoe.NewGame()}, nil})
+}
+
+func ExampleFromV1() {
+	game := FromV1(v1TTTGame{tictactoe.NewGame()})
+	fmt.Println(game.Len())
+	// Output: 9
+}
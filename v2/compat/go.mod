@@ -0,0 +1,14 @@
+module github.com/0xhexnumbers/gmcts/v2/compat
+
+go 1.14
+
+require (
+	github.com/0xhexnumbers/gmcts v1.0.0
+	github.com/0xhexnumbers/gmcts/v2 v2.0.0
+	github.com/0xhexnumbers/go-tic-tac-toe v0.2.2
+)
+
+replace (
+	github.com/0xhexnumbers/gmcts => ../../
+	github.com/0xhexnumbers/gmcts/v2 => ../
+)
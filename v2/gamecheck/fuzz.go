@@ -0,0 +1,75 @@
+package gamecheck
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+)
+
+//Walk applies the actions encoded by the given data to the initial
+//state, and returns the state reached along with the path of actions
+//taken. Each byte selects an action, modulo the number of actions of
+//the current state. The walk ends early if a terminal state is reached.
+//
+//Walk panics if the game returns an error on any action.
+func Walk(initial gmcts.Game, data []byte) (gmcts.Game, []int) {
+	path := make([]int, 0, len(data))
+	game := walk(initial, data, &path)
+	return game, path
+}
+
+//walk applies the actions encoded by the given data to the initial state,
+//appending each action to path as soon as it is taken.
+func walk(initial gmcts.Game, data []byte, path *[]int) gmcts.Game {
+	game := initial
+	for _, b := range data {
+		if game.IsTerminal() || game.Len() <= 0 {
+			break
+		}
+
+		action := int(b) % game.Len()
+		next, err := game.ApplyAction(action)
+		if err != nil {
+			panic(fmt.Sprintf("gamecheck: ApplyAction(%d) returned an error: %s", action, err))
+		}
+		game = next
+		*path = append(*path, action)
+	}
+	return game
+}
+
+//FuzzTarget returns a fuzz target to be passed to testing.F's Fuzz method.
+//
+//The fuzzer's input is turned into a path of actions from a new game as
+//done by Walk. The state reached is checked like Check does, and is then
+//searched for the given number of rounds. Failed checks and panics from
+//the game or the search fail the fuzz target with the path of actions
+//that reproduces them, letting the fuzzer save the input to its corpus.
+func FuzzTarget(newGame func() gmcts.Game, rounds int) func(*testing.T, []byte) {
+	return func(t *testing.T, data []byte) {
+		var path []int
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("gamecheck: panic after actions %v: %v", path, r)
+			}
+		}()
+
+		game := walk(newGame(), data, &path)
+
+		if reason := checkState(game); reason != "" {
+			t.Fatal(&Failure{path, reason})
+		}
+		if game.IsTerminal() {
+			return
+		}
+
+		mcts := gmcts.NewMCTS(game)
+		tree := mcts.SpawnTree()
+		tree.SearchRounds(rounds)
+		mcts.AddTree(tree)
+		if _, err := mcts.BestAction(); err != nil {
+			t.Fatalf("gamecheck: no best action after actions %v: %s", path, err)
+		}
+	}
+}
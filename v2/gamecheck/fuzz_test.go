@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package gamecheck
+
+import (
+	"testing"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+	"github.com/0xhexnumbers/gmcts/v2/internal/testgames"
+)
+
+func FuzzTicTacToe(f *testing.F) {
+	f.Add([]byte{4, 0, 8})
+	f.Fuzz(FuzzTarget(func() gmcts.Game {
+		return testgames.NewTicTacToe()
+	}, 100))
+}
@@ -0,0 +1,182 @@
+//Package gamecheck checks implementations of gmcts.Game for
+//common mistakes, such as mutable states, unstable or noncomparable
+//hashes, and inconsistent terminal states.
+//
+//The game is checked by randomly walking through it from an initial
+//state. Any failure found is reported along with the path of actions
+//that reproduces it from the initial state.
+//
+//Games may also be fuzzed with Go's native fuzzing by passing
+//FuzzTarget to testing.F's Fuzz method:
+//
+//	func FuzzGame(f *testing.F) {
+//		f.Fuzz(gamecheck.FuzzTarget(NewGame, 100))
+//	}
+package gamecheck
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+)
+
+//Config configures how a game is walked through.
+type Config struct {
+	//Walks is the number of random walks to take from the initial state
+	Walks int
+
+	//MaxLength is the most actions taken in a single walk
+	MaxLength int
+
+	//Seed seeds the random actions taken
+	Seed int64
+}
+
+//DefaultConfig is the configuration used by Test.
+var DefaultConfig = Config{
+	Walks:     100,
+	MaxLength: 1000,
+}
+
+//Failure describes a check a game state failed.
+type Failure struct {
+	//Path is the list of actions applied to the initial
+	//state to reach the failing state
+	Path []int
+
+	//Reason describes the check that failed
+	Reason string
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("gamecheck: %s (actions from initial state: %v)", f.Reason, f.Path)
+}
+
+//Test checks the game using DefaultConfig, failing the test
+//if any check fails.
+func Test(t testing.TB, initial gmcts.Game) {
+	t.Helper()
+	if err := Check(initial, DefaultConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//Check randomly walks through the game from the initial state, checking
+//every state reached. Check returns a *Failure for the first check that
+//fails, or nil if every check passes.
+func Check(initial gmcts.Game, c Config) error {
+	randSource := rand.New(rand.NewSource(c.Seed))
+	for walk := 0; walk < c.Walks; walk++ {
+		var path []int
+		game := initial
+		for {
+			if reason := checkState(game); reason != "" {
+				return &Failure{path, reason}
+			}
+			if game.IsTerminal() || len(path) >= c.MaxLength {
+				break
+			}
+
+			action := randSource.Intn(game.Len())
+			game, _ = game.ApplyAction(action)
+			path = append(path, action)
+		}
+	}
+	return nil
+}
+
+//checkState checks a single game state, returning the
+//reason for the first check that fails.
+func checkState(game gmcts.Game) (reason string) {
+	//Comparing noncomparable hashes panics
+	defer func() {
+		if r := recover(); r != nil {
+			reason = fmt.Sprintf("game panicked: %v", r)
+		}
+	}()
+
+	hash := game.Hash()
+	if hash != nil && !reflect.TypeOf(hash).Comparable() {
+		return fmt.Sprintf("Hash() returned a noncomparable value of type %T", hash)
+	}
+	if !equal(hash, game.Hash()) {
+		return "Hash() returned different values on the same state"
+	}
+
+	actions, player, terminal := game.Len(), game.Player(), game.IsTerminal()
+	if actions != game.Len() || player != game.Player() || terminal != game.IsTerminal() {
+		return "Len(), Player() or IsTerminal() returned different values on the same state"
+	}
+
+	if terminal {
+		winners := game.Winners()
+		if len(winners) == 0 {
+			return "Winners() returned no players on a terminal state"
+		}
+		if counter, ok := game.(gmcts.PlayerCounter); ok {
+			for _, p := range winners {
+				if p < 0 || int(p) >= counter.NumPlayers() {
+					return fmt.Sprintf("Winners() returned player %d, outside of the %d players declared by NumPlayers()", p, counter.NumPlayers())
+				}
+			}
+		}
+		return ""
+	}
+	if actions <= 0 {
+		return fmt.Sprintf("Len() returned %d on a non-terminal state", actions)
+	}
+
+	for i := 0; i < actions; i++ {
+		next, err := game.ApplyAction(i)
+		if err != nil {
+			return fmt.Sprintf("ApplyAction(%d) returned an error on a valid action: %s", i, err)
+		}
+		if next == nil {
+			return fmt.Sprintf("ApplyAction(%d) returned a nil game", i)
+		}
+
+		//Applying the same action again must reach the same state
+		again, _ := game.ApplyAction(i)
+		if nextHash := next.Hash(); again == nil || !equal(nextHash, again.Hash()) {
+			return fmt.Sprintf("ApplyAction(%d) reached states with different hashes when applied twice", i)
+		}
+
+		//Applying an action must not change the state it was applied to
+		if !equal(hash, game.Hash()) || actions != game.Len() ||
+			player != game.Player() || terminal != game.IsTerminal() {
+			return fmt.Sprintf("ApplyAction(%d) changed the state it was applied to", i)
+		}
+
+		//Making an action in place must reach the same state as applying
+		//it, and unmaking it must restore the original state
+		if mutable, ok := game.(gmcts.MutableGame); ok {
+			clone := mutable.Clone()
+			if err := clone.Do(i); err != nil {
+				return fmt.Sprintf("Do(%d) returned an error on a valid action: %s", i, err)
+			}
+			//Undo would hide any change made to the original state
+			if !equal(hash, game.Hash()) || actions != game.Len() {
+				return "Clone() returned a state sharing memory with the original state"
+			}
+			if !equal(next.Hash(), clone.Hash()) {
+				return fmt.Sprintf("Do(%d) and ApplyAction(%d) reached states with different hashes", i, i)
+			}
+			clone.Undo()
+			if !equal(hash, clone.Hash()) || actions != clone.Len() || player != clone.Player() {
+				return fmt.Sprintf("Undo() did not restore the state after Do(%d)", i)
+			}
+		}
+	}
+	return ""
+}
+
+//equal compares two hashes, reporting noncomparable hashes as unequal
+func equal(a, b interface{}) bool {
+	if a != nil && !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,144 @@
+package gamecheck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+	"github.com/0xhexnumbers/gmcts/v2/internal/testgames"
+)
+
+//sliceHashGame returns a noncomparable hash
+type sliceHashGame struct {
+	testgames.TicTacToe
+}
+
+func (g sliceHashGame) Hash() interface{} {
+	return g.Actions
+}
+
+//noWinnersGame returns no winners on terminal states
+type noWinnersGame struct {
+	testgames.TicTacToe
+}
+
+func (g noWinnersGame) ApplyAction(i int) (gmcts.Game, error) {
+	game, err := g.TicTacToe.ApplyAction(i)
+	return noWinnersGame{game.(testgames.TicTacToe)}, err
+}
+
+func (g noWinnersGame) Winners() []gmcts.Player {
+	return nil
+}
+
+//mutableGame changes its own state when an action is applied
+type mutableGame struct {
+	moves *int
+}
+
+func (g mutableGame) Len() int {
+	return 2
+}
+
+func (g mutableGame) ApplyAction(i int) (gmcts.Game, error) {
+	*g.moves++
+	return g, nil
+}
+
+func (g mutableGame) Hash() interface{} {
+	return *g.moves
+}
+
+func (g mutableGame) Player() gmcts.Player {
+	return 0
+}
+
+func (g mutableGame) IsTerminal() bool {
+	return *g.moves > 10
+}
+
+func (g mutableGame) Winners() []gmcts.Player {
+	return []gmcts.Player{0}
+}
+
+//sharedCloneGame returns clones that share their moves with the original state
+type sharedCloneGame struct {
+	mutableGame
+}
+
+func (g sharedCloneGame) ApplyAction(i int) (gmcts.Game, error) {
+	moves := *g.moves + 1
+	return sharedCloneGame{mutableGame{&moves}}, nil
+}
+
+func (g sharedCloneGame) Do(i int) error {
+	*g.moves++
+	return nil
+}
+
+func (g sharedCloneGame) Undo() {
+	*g.moves--
+}
+
+func (g sharedCloneGame) Clone() gmcts.MutableGame {
+	return g
+}
+
+func TestTicTacToe(t *testing.T) {
+	Test(t, testgames.NewTicTacToe())
+}
+
+func TestFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		game   gmcts.Game
+		reason string
+	}{
+		{"noncomparable hash", sliceHashGame{testgames.NewTicTacToe()}, "noncomparable"},
+		{"no winners", noWinnersGame{testgames.NewTicTacToe()}, "no players on a terminal state"},
+		{"mutable state", mutableGame{new(int)}, "changed the state it was applied to"},
+		{"shared clone", sharedCloneGame{mutableGame{new(int)}}, "sharing memory"},
+	}
+
+	for _, test := range tests {
+		err := Check(test.game, DefaultConfig)
+		failure, ok := err.(*Failure)
+		if !ok {
+			t.Errorf("%s: Check returned %v: wanted a *Failure", test.name, err)
+			continue
+		}
+		if !strings.Contains(failure.Reason, test.reason) {
+			t.Errorf("%s: Check failed with %q: wanted %q", test.name, failure.Reason, test.reason)
+		}
+	}
+}
+
+func TestFailurePath(t *testing.T) {
+	//The failing state must be reachable by following the reported path
+	err := Check(noWinnersGame{testgames.NewTicTacToe()}, DefaultConfig)
+	failure := err.(*Failure)
+
+	var game gmcts.Game = noWinnersGame{testgames.NewTicTacToe()}
+	for _, action := range failure.Path {
+		game, _ = game.ApplyAction(action)
+	}
+	if !game.IsTerminal() {
+		t.Errorf("Following the path %v did not reach the failing terminal state", failure.Path)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	//Bytes select actions modulo the number of actions of each state,
+	//and the walk ends once a terminal state is reached
+	data := []byte{4, 8, 7, 7, 6, 4, 3, 2, 1, 0, 0, 0}
+	game, path := Walk(testgames.NewTicTacToe(), data)
+
+	want := []int{4, 0, 0, 1, 1, 0, 0, 0, 0}
+	if fmt.Sprint(path) != fmt.Sprint(want) {
+		t.Errorf("Walk took actions %v: wanted %v", path, want)
+	}
+	if !game.IsTerminal() {
+		t.Errorf("Walk did not reach a terminal state")
+	}
+}
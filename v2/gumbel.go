@@ -0,0 +1,191 @@
+package gmcts
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	//gumbelVisitConst and gumbelScaleConst scale the win rates of
+	//actions compared by Gumbel search, as c_visit and c_scale do in
+	//https://openreview.net/forum?id=bERaNdoegnO
+	gumbelVisitConst = 50
+	gumbelScaleConst = 1.0
+)
+
+//SetGumbel enables Gumbel search for SearchRounds, sampling the given
+//number of the root's actions to search. An amount of 0, which is the
+//default, disables Gumbel search.
+//
+//Gumbel search samples actions of the root without replacement, with a
+//probability given by the tree's prior, using the Gumbel-top-k trick. The
+//rounds given to SearchRounds are then split between the sampled actions
+//with sequential halving, comparing actions by their perturbed prior and
+//win rate. The best action of the tree is the action remaining after the
+//last call to SearchRounds. This finds strong actions with few rounds,
+//especially with a good prior. Below the root, children are selected as usual.
+//
+//Gumbel search takes precedence over sequential halving,
+//and early stopping is ignored while it is enabled.
+func (t *Tree) SetGumbel(actions int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.gumbelActions = actions
+	t.gumbelResult = nil
+}
+
+//ImprovedPolicy returns the improved policy of the root, indexed by
+//action, or nil if the root has not been expanded yet. The improved
+//policy is the tree's prior updated with the win rates found by the
+//search, where actions that have not been visited are given the
+//expected value of the root. It can be used as a training target
+//for a prior.
+func (t *Tree) ImprovedPolicy() []float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	root := t.current
+	if root.actionCount == 0 {
+		return nil
+	}
+	logits := t.logits(root)
+	prior := softmax(logits)
+	player := root.state.Player()
+
+	//Mix the root's value with the prior's expectation
+	//of the win rates of the visited actions
+	var visits, visitedPrior, expectation float64
+	for i, v := range root.childVisits {
+		if v > 0 {
+			visits += v
+			visitedPrior += prior[i]
+			expectation += prior[i] * root.winRate(i, player)
+		}
+	}
+	value := 0.5
+	if root.nodeVisits > 0 {
+		value = root.score(player) / float64(root.nodeVisits)
+	}
+	if visitedPrior > 0 {
+		value = (value + visits*expectation/visitedPrior) / (1 + visits)
+	}
+
+	for i, v := range root.childVisits {
+		q := value
+		if v > 0 {
+			q = root.winRate(i, player)
+		}
+		logits[i] += t.gumbelSigma(q)
+	}
+	return softmax(logits)
+}
+
+//searchGumbel performs the given number of rounds,
+//selecting the root's actions with Gumbel search
+func (t *Tree) searchGumbel(rounds int) {
+	actionCount := t.expandRoot()
+
+	t.mutex.Lock()
+	t.gumbel = t.logits(t.current)
+	actions := make([]int, actionCount)
+	for i := range actions {
+		t.gumbel[i] += sampleGumbel(t.randSource)
+		actions[i] = i
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return t.gumbel[actions[i]] > t.gumbel[actions[j]]
+	})
+	if len(actions) > t.gumbelActions {
+		actions = actions[:t.gumbelActions]
+	}
+	t.gumbelResult = nil
+	t.mutex.Unlock()
+
+	remaining := t.halve(actions, rounds, func(root *node, action int) float64 {
+		return t.gumbel[action] + t.gumbelSigma(root.winRate(action, root.state.Player()))
+	})
+
+	t.mutex.Lock()
+	t.gumbelResult = remaining
+	t.mutex.Unlock()
+}
+
+//gumbelAction returns the best of the actions remaining
+//after the last Gumbel search of the root
+func (t *Tree) gumbelAction() int {
+	root := t.current
+	player := root.state.Player()
+	bestAction := t.gumbelResult[0]
+	bestScore := math.Inf(-1)
+	for _, action := range t.gumbelResult {
+		if score := t.gumbel[action] + t.gumbelSigma(root.winRate(action, player)); score > bestScore {
+			bestAction, bestScore = action, score
+		}
+	}
+	return bestAction
+}
+
+//gumbelSigma scales a win rate of one of the root's actions,
+//so that win rates matter more as the root's actions are visited
+func (t *Tree) gumbelSigma(q float64) float64 {
+	_, maxVisits, _ := t.current.mostVisited()
+	return (gumbelVisitConst + maxVisits) * gumbelScaleConst * q
+}
+
+//logits returns the log of the prior probability of each action of
+//the given expanded node, which are all 0 if the node has no priors
+func (t *Tree) logits(n *node) []float64 {
+	logits := make([]float64, n.actionCount)
+	for i, p := range n.priors {
+		logits[i] = math.Log(p)
+	}
+	return logits
+}
+
+//winRate returns the win rate of the ith child
+//of this node for the given player
+func (n *node) winRate(i int, p Player) float64 {
+	score, visits := n.winStats(i, p)
+	return score / visits
+}
+
+//winStats returns the score of the ith child of this node for the
+//given player, along with the visits its win rate is taken over
+func (n *node) winStats(i int, p Player) (float64, float64) {
+	//In graph mode, a child's score builds up from every
+	//path reaching it, not only from the visits of this node
+	child := n.children[i]
+	if n.tree.graph {
+		return child.score(p), float64(child.nodeVisits)
+	}
+	return child.score(p), n.childVisits[i]
+}
+
+//softmax returns the probabilities given by the exponent of each logit
+func softmax(logits []float64) []float64 {
+	maxLogit := math.Inf(-1)
+	for _, logit := range logits {
+		maxLogit = math.Max(maxLogit, logit)
+	}
+
+	probabilities := make([]float64, len(logits))
+	var total float64
+	for i, logit := range logits {
+		probabilities[i] = math.Exp(logit - maxLogit)
+		total += probabilities[i]
+	}
+	for i := range probabilities {
+		probabilities[i] /= total
+	}
+	return probabilities
+}
+
+//sampleGumbel samples a standard Gumbel distribution
+func sampleGumbel(randSource *rand.Rand) float64 {
+	u := randSource.Float64()
+	for u == 0 {
+		u = randSource.Float64()
+	}
+	return -math.Log(-math.Log(u))
+}
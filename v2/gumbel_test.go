@@ -0,0 +1,104 @@
+package gmcts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+//centerPrior favors the actions of tic-tac-toe closest to the middle
+func centerPrior(g Game) []float64 {
+	game := g.(tttGame)
+	prior := make([]float64, len(game.actions))
+	var total float64
+	for i, action := range game.actions {
+		switch square := fmt.Sprintf("%v", action); {
+		case square == "{1 1}":
+			prior[i] = 4
+		case strings.Contains(square, "1"):
+			prior[i] = 2
+		default:
+			prior[i] = 1
+		}
+		total += prior[i]
+	}
+	for i := range prior {
+		prior[i] /= total
+	}
+	return prior
+}
+
+func TestGumbel(t *testing.T) {
+	mcts := NewMCTS(newGame)
+	for i := 0; i < 4; i++ {
+		tree := mcts.SpawnTree()
+		tree.SetPrior(centerPrior)
+		tree.SetGumbel(4)
+		tree.SearchRounds(64)
+		mcts.AddTree(tree)
+
+		if rounds := tree.Rounds(); rounds != 64 {
+			t.Errorf("Tree with Gumbel search performed %d rounds: wanted 64", rounds)
+			t.FailNow()
+		}
+	}
+
+	bestAction, _ := mcts.BestAction()
+	if fmt.Sprintf("%v", newGame.actions[bestAction]) != "{1 1}" {
+		t.Errorf("Trees with Gumbel search picked %v: wanted {1 1}", newGame.actions[bestAction])
+		t.FailNow()
+	}
+}
+
+func TestGumbelSampledActions(t *testing.T) {
+	//Without a prior, only the sampled actions should be searched
+	tree := NewMCTS(newGame).SpawnTree()
+	tree.SetGumbel(2)
+	tree.SearchRounds(16)
+
+	var visited int
+	for _, visits := range tree.current.childVisits {
+		if visits > 0 {
+			visited++
+		}
+	}
+	if visited != 2 {
+		t.Errorf("Tree sampling 2 actions with Gumbel search visited %d actions: wanted 2", visited)
+		t.FailNow()
+	}
+	if best := tree.bestAction(); tree.current.childVisits[best] == 0 {
+		t.Errorf("Tree with Gumbel search picked action %d, which was not sampled", best)
+		t.FailNow()
+	}
+}
+
+func TestImprovedPolicy(t *testing.T) {
+	tree := NewMCTS(newGame).SpawnTree()
+	if policy := tree.ImprovedPolicy(); policy != nil {
+		t.Errorf("Tree returned improved policy %v before searching: wanted nil", policy)
+		t.FailNow()
+	}
+
+	tree.SetPrior(centerPrior)
+	tree.SetGumbel(9)
+	tree.SearchRounds(1000)
+
+	policy := tree.ImprovedPolicy()
+	var total float64
+	best := 0
+	for i, p := range policy {
+		total += p
+		if p > policy[best] {
+			best = i
+		}
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Improved policy sums to %f: wanted 1", total)
+		t.FailNow()
+	}
+	if fmt.Sprintf("%v", newGame.actions[best]) != "{1 1}" {
+		t.Errorf("Improved policy favors %v: wanted {1 1}", newGame.actions[best])
+		t.FailNow()
+	}
+}
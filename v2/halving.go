@@ -0,0 +1,132 @@
+package gmcts
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+//SetSequentialHalving enables or disables sequential halving for SearchRounds.
+//
+//With sequential halving, the rounds given to SearchRounds are split into
+//phases. Each phase, the rounds of the phase are split evenly between the
+//root's remaining actions, and the worse half of the actions, by win rate,
+//are discarded at the end of the phase. Below the root, children are
+//selected as usual. The best action of the tree is the action remaining
+//after the last call to SearchRounds, even if an action discarded earlier
+//has a higher win rate. This explores the root's actions more evenly than
+//UCT when the number of rounds is known in advance.
+//
+//Early stopping is ignored while sequential halving is enabled.
+func (t *Tree) SetSequentialHalving(enabled bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.sequentialHalving = enabled
+	t.halvingResult = nil
+}
+
+//searchHalving performs the given number of rounds,
+//selecting the root's actions with sequential halving
+func (t *Tree) searchHalving(rounds int) {
+	actions := make([]int, t.expandRoot())
+	for i := range actions {
+		actions[i] = i
+	}
+
+	t.mutex.Lock()
+	t.halvingResult = nil
+	t.mutex.Unlock()
+
+	remaining := t.halve(actions, rounds, func(root *node, action int) float64 {
+		return root.winRate(action, root.state.Player())
+	})
+
+	t.mutex.Lock()
+	t.halvingResult = remaining
+	t.mutex.Unlock()
+}
+
+//halvingAction returns the best of the actions remaining
+//after the last sequential halving of the root
+func (t *Tree) halvingAction() int {
+	root := t.current
+	player := root.state.Player()
+	bestAction := t.halvingResult[0]
+	for _, action := range t.halvingResult {
+		if root.winRate(action, player) > root.winRate(bestAction, player) {
+			bestAction = action
+		}
+	}
+	return bestAction
+}
+
+//expandRoot expands the root if it has not been expanded
+//yet, and returns the number of actions of the root
+func (t *Tree) expandRoot() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	root := t.current
+	if root.actionCount == 0 && !root.state.IsTerminal() {
+		root.expand(0)
+		if t.observer != nil {
+			t.observer.Expand(ExpandEvent{0, root.state.hash, root.actionCount})
+		}
+	}
+	return root.actionCount
+}
+
+//halve performs the given number of rounds, split between the given
+//actions of the root with sequential halving, and returns the actions
+//remaining once the rounds are spent. Actions are ranked by the given
+//function, where higher is better, at the end of each phase.
+func (t *Tree) halve(actions []int, rounds int, rank func(root *node, action int) float64) []int {
+	ctx := context.Background()
+	if len(actions) == 0 {
+		//The root is terminal, so there are no actions to split rounds between
+		for ; rounds > 0; rounds-- {
+			t.search(ctx)
+		}
+		return actions
+	}
+
+	for len(actions) > 1 && rounds > 0 {
+		phasesLeft := int(math.Ceil(math.Log2(float64(len(actions)))))
+		perAction := rounds / (len(actions) * phasesLeft)
+		if perAction == 0 {
+			perAction = 1
+		}
+		for i := 0; i < perAction; i++ {
+			for _, action := range actions {
+				if rounds == 0 {
+					break
+				}
+				t.searchChild(ctx, action)
+				rounds--
+			}
+		}
+
+		//Keep the better half of the actions, leaving out any action
+		//that could not be visited with the rounds left
+		t.mutex.Lock()
+		root := t.current
+		visited := actions[:0]
+		for _, action := range actions {
+			if root.childVisits[action] > 0 {
+				visited = append(visited, action)
+			}
+		}
+		actions = visited
+		sort.SliceStable(actions, func(i, j int) bool {
+			return rank(root, actions[i]) > rank(root, actions[j])
+		})
+		actions = actions[:(len(actions)+1)/2]
+		t.mutex.Unlock()
+	}
+
+	//Spend any rounds left over on the last remaining action
+	for ; rounds > 0; rounds-- {
+		t.searchChild(ctx, actions[0])
+	}
+	return actions
+}
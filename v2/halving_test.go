@@ -0,0 +1,112 @@
+package gmcts
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSequentialHalving(t *testing.T) {
+	mcts := NewMCTS(newGame)
+	tree := mcts.SpawnTree()
+	tree.SetSequentialHalving(true)
+	tree.SetEarlyStop(true)
+	tree.SearchRounds(2000)
+	mcts.AddTree(tree)
+
+	if rounds := tree.Rounds(); rounds != 2000 {
+		t.Errorf("Tree with sequential halving performed %d rounds: wanted 2000", rounds)
+		t.FailNow()
+	}
+
+	//The last remaining action should be among the most visited
+	bestAction, _ := mcts.BestAction()
+	_, mostVisits, _ := tree.current.mostVisited()
+	if fmt.Sprintf("%v", newGame.actions[bestAction]) != "{1 1}" || tree.current.childVisits[bestAction] != mostVisits {
+		t.Errorf("Tree with sequential halving picked %v with %.0f visits: wanted {1 1} with %.0f visits",
+			newGame.actions[bestAction], tree.current.childVisits[bestAction], mostVisits)
+		t.FailNow()
+	}
+
+	//Every action should have been searched in the first phase
+	for i, visits := range tree.current.childVisits {
+		if visits < 2000/(9*4) {
+			t.Errorf("Tree with sequential halving visited action %d %.0f times: wanted >= %d", i, visits, 2000/(9*4))
+			t.FailNow()
+		}
+	}
+}
+
+func TestSequentialHalvingTerminal(t *testing.T) {
+	tree := NewMCTS(finishedGame).SpawnTree()
+	tree.SetSequentialHalving(true)
+	tree.SearchRounds(10)
+	if rounds := tree.Rounds(); rounds != 10 {
+		t.Errorf("Tree with sequential halving performed %d rounds on a terminal state: wanted 10", rounds)
+		t.FailNow()
+	}
+}
+
+//gambleGame is a game where the first player either gambles on
+//the second player's choice between a win and a loss, or takes a draw
+type gambleGame string
+
+func (g gambleGame) Len() int {
+	switch g {
+	case "":
+		return 2
+	case "0":
+		return 2
+	case "1":
+		return 1
+	}
+	return 0
+}
+
+func (g gambleGame) ApplyAction(i int) (Game, error) {
+	return g + gambleGame(fmt.Sprint(i)), nil
+}
+
+func (g gambleGame) Hash() interface{} {
+	return g
+}
+
+func (g gambleGame) Player() Player {
+	return Player(len(g)%2 + 1)
+}
+
+func (g gambleGame) IsTerminal() bool {
+	return len(g) == 2
+}
+
+func (g gambleGame) Winners() []Player {
+	switch g {
+	case "00":
+		return []Player{2}
+	case "01":
+		return []Player{1}
+	}
+	return []Player{1, 2}
+}
+
+func TestSequentialHalvingSurvivor(t *testing.T) {
+	//The gamble and the draw tie in the first phase, and the gamble
+	//is kept. The last round then loses the gamble, so the discarded
+	//draw ends with the higher win rate.
+	tree := NewMCTS(gambleGame("")).SpawnTree()
+	tree.SetSequentialHalving(true)
+	tree.SearchRounds(5)
+
+	root := tree.current
+	if gamble, draw := root.winRate(0, 1), root.winRate(1, 1); draw <= gamble {
+		t.Errorf("Gamble has a win rate of %.2f and draw has %.2f: wanted the draw to be higher", gamble, draw)
+		t.FailNow()
+	}
+	if bestAction := tree.bestAction(); bestAction != 0 {
+		t.Errorf("Tree with sequential halving picked %d: wanted the remaining action 0", bestAction)
+		t.FailNow()
+	}
+	if info := tree.Info(); info.BestAction != 0 || info.Visits != 3 {
+		t.Errorf("Tree with sequential halving reported %+v: wanted action 0 with 3 visits", info)
+		t.FailNow()
+	}
+}
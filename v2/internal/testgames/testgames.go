@@ -0,0 +1,97 @@
+//Package testgames holds the games shared by the tests of gmcts' packages.
+package testgames
+
+import (
+	"github.com/0xhexnumbers/gmcts/v2"
+	tictactoe "github.com/0xhexnumbers/go-tic-tac-toe"
+)
+
+//Nim is a game of nim with a single pile, where players take
+//1 to MaxTake stones, and the player taking the last stone wins
+type Nim struct {
+	Stones  int
+	MaxTake int
+	Turn    gmcts.Player
+}
+
+func (g Nim) Len() int {
+	if g.Stones < g.MaxTake {
+		return g.Stones
+	}
+	return g.MaxTake
+}
+
+func (g Nim) ApplyAction(i int) (gmcts.Game, error) {
+	return Nim{g.Stones - i - 1, g.MaxTake, 1 - g.Turn}, nil
+}
+
+func (g Nim) Hash() interface{} {
+	return g
+}
+
+func (g Nim) Player() gmcts.Player {
+	return g.Turn
+}
+
+func (g Nim) IsTerminal() bool {
+	return g.Stones == 0
+}
+
+func (g Nim) Winners() []gmcts.Player {
+	return []gmcts.Player{1 - g.Turn}
+}
+
+//TicTacToe is a game of tic-tac-toe, where x is player 0 and o is player 1
+type TicTacToe struct {
+	Game    tictactoe.Game
+	Actions []tictactoe.Move
+}
+
+//NewTicTacToe returns the initial state of a game of tic-tac-toe.
+func NewTicTacToe() TicTacToe {
+	game := tictactoe.NewGame()
+	return TicTacToe{game, game.GetActions()}
+}
+
+func (g TicTacToe) Len() int {
+	return len(g.Actions)
+}
+
+func (g TicTacToe) ApplyAction(i int) (gmcts.Game, error) {
+	game, err := g.Game.ApplyAction(g.Actions[i])
+	return TicTacToe{game, game.GetActions()}, err
+}
+
+func (g TicTacToe) Hash() interface{} {
+	return g.Game
+}
+
+func (g TicTacToe) Player() gmcts.Player {
+	return PlayerID(g.Game.Player())
+}
+
+func (g TicTacToe) IsTerminal() bool {
+	return g.Game.IsTerminal()
+}
+
+func (g TicTacToe) Winners() []gmcts.Player {
+	return Winners(g.Game)
+}
+
+//PlayerID returns the player playing the given tic-tac-toe mark.
+func PlayerID(mark byte) gmcts.Player {
+	if mark == 'x' || mark == 'X' {
+		return 0
+	}
+	return 1
+}
+
+//Winners returns the winners of a tic-tac-toe game, which
+//are both players if the game has no winner.
+func Winners(game tictactoe.Game) []gmcts.Player {
+	winner, _ := game.Winner()
+	if winner == '_' {
+		return []gmcts.Player{0, 1}
+	}
+	return []gmcts.Player{PlayerID(winner)}
+}
@@ -2,6 +2,7 @@ package gmcts
 
 import (
 	"errors"
+	"math"
 	"math/rand"
 	"sync"
 )
@@ -15,6 +16,9 @@ var (
 
 	//ErrNoActions notifies the callee that the given state has <= 0 actions
 	ErrNoActions = errors.New("gmcts: given game state is not terminal, yet the state has <= 0 actions to search through")
+
+	//ErrNoConfig notifies the callee that no search configuration was given for the player to move
+	ErrNoConfig = errors.New("gmcts: no search configuration was given for the player to move")
 )
 
 //NewMCTS returns a new MCTS wrapper
@@ -41,6 +45,77 @@ func (m *MCTS) SetSeed(seed int64) {
 	m.seed = seed
 }
 
+//SetGraphMode sets whether the next trees to be spawned search in graph mode.
+//
+//By default, states reached on different turns are kept as separate nodes,
+//so the tree never contains cycles. In graph mode, every state reached is
+//shared by a single node regardless of the turn it is reached on, and states
+//repeated on the path being searched are scored using the game's Repeater
+//implementation. As random playouts may cycle forever in graph mode, a
+//maximum rollout depth should be set on the spawned trees.
+func (m *MCTS) SetGraphMode(enabled bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.graph = enabled
+}
+
+//SetTranspositionMode sets how the next trees to be spawned handle
+//states reached from multiple parents. The default is TranspositionsUCT2.
+func (m *MCTS) SetTranspositionMode(mode TranspositionMode) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.transpositions = mode
+}
+
+//SetCollisionMode sets how the next trees to be spawned handle distinct
+//states with the same hash. The default is CollisionsUnchecked.
+//
+//Checking for collisions compares states every time a state is found
+//in a tree's cache, which slows down the search.
+//
+//SetCollisionMode will panic if the mode checks for collisions
+//and the initial state does not implement Equaler.
+func (m *MCTS) SetCollisionMode(mode CollisionMode) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.init.(Equaler); !ok && mode != CollisionsUnchecked {
+		panic("gmcts: checking for collisions requires the game to implement Equaler")
+	}
+	m.collisions = mode
+}
+
+//SetDirichletNoise sets the Dirichlet noise mixed into the priors of the
+//root of the next trees to be spawned, which varies the actions explored
+//by the trees, such as to generate varied games of self-play. The noise
+//makes up the given fraction of the priors, and is sampled with the given
+//concentration alpha, using the seed of each tree. A fraction of 0, which
+//is the default, disables the noise.
+//
+//Priors are only used by trees searching with PUCT or Gumbel search.
+//Roots of trees without a prior are given the same prior for each
+//action before the noise is mixed in.
+func (m *MCTS) SetDirichletNoise(alpha, fraction float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.noiseAlpha = alpha
+	m.noiseFraction = fraction
+}
+
+//SetTemperature sets the temperature BestAction samples actions with.
+//
+//With a temperature above 0, BestAction samples an action with a
+//probability proportional to the visits made to it by every collected
+//tree, raised to the power of 1 divided by the temperature. A temperature
+//of 1 samples actions in proportion to their visits, while temperatures
+//closer to 0 favor the most visited action. The action is sampled using
+//the random source of the first collected tree. A temperature of 0,
+//which is the default, lets the trees vote on the best action instead.
+func (m *MCTS) SetTemperature(temperature float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.temperature = temperature
+}
+
 //SpawnCustomTree creates a new search tree with a given exploration constant.
 func (m *MCTS) SpawnCustomTree(explorationConst float64) *Tree {
 	m.mutex.Lock()
@@ -50,8 +125,30 @@ func (m *MCTS) SpawnCustomTree(explorationConst float64) *Tree {
 		gameStates:       make(map[gameHash]*node),
 		explorationConst: explorationConst,
 		randSource:       rand.New(rand.NewSource(m.seed)),
+		mutex:            new(sync.Mutex),
+		graph:            m.graph,
+		transpositions:   m.transpositions,
+		collisions:       m.collisions,
+		noiseAlpha:       m.noiseAlpha,
+		noiseFraction:    m.noiseFraction,
+		collided:         make(map[gameHash][]*node),
+	}
+	if counter, ok := m.init.(PlayerCounter); ok {
+		t.players = counter.NumPlayers()
+	}
+	//Mutable states are changed in place during a search,
+	//so each tree needs a copy of its own
+	init := m.init
+	if mutable, ok := init.(MutableGame); ok {
+		init = mutable.Clone()
+	}
+	t.current = initializeNode(gameState{init, gameHash{init.Hash(), 0}}, t)
+
+	//In graph mode, the root may be reached again from its descendants
+	if t.graph {
+		t.gameStates[t.current.state.gameHash] = t.current
+		t.nodeCount++
 	}
-	t.current = initializeNode(gameState{m.init, gameHash{m.init.Hash(), 0}}, t)
 
 	m.seed++
 	return t
@@ -66,6 +163,26 @@ func (m *MCTS) AddTree(t *Tree) {
 	m.trees = append(m.trees, t)
 }
 
+//Reroot changes the state the MCTS wrapper is considering to the given
+//state, such as the state after an opponent has made their move.
+//
+//Each collected tree keeps the subtree matching the given state.
+//Trees that have not searched through the given state are discarded.
+//Reroot is safe to call while the collected trees are pondering.
+func (m *MCTS) Reroot(state Game) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.init = state
+	trees := m.trees[:0]
+	for _, t := range m.trees {
+		if t.Reroot(state) {
+			trees = append(trees, t)
+		}
+	}
+	m.trees = trees
+}
+
 //BestAction takes all of the searched trees and returns
 //the index of the best action based on the highest win
 //percentage of each action.
@@ -79,12 +196,14 @@ func (m *MCTS) BestAction() (int, error) {
 	defer m.mutex.RUnlock()
 
 	//Error checking
-	if len(m.trees) == 0 {
-		return -1, ErrNoTrees
-	} else if m.init.IsTerminal() {
-		return -1, ErrTerminal
-	} else if m.init.Len() <= 0 {
-		return -1, ErrNoActions
+	if err := m.check(); err != nil {
+		return -1, err
+	}
+
+	if m.temperature > 0 {
+		if action, ok := m.sampleAction(); ok {
+			return action, nil
+		}
 	}
 
 	//Democracy Section: each tree votes for an action
@@ -104,3 +223,106 @@ func (m *MCTS) BestAction() (int, error) {
 	}
 	return bestAction, nil
 }
+
+//sampleAction samples an action in proportion to the visits made to it
+//by every collected tree, raised to the power of 1 divided by the
+//temperature. It returns false if no tree has visited any action.
+func (m *MCTS) sampleAction() (int, bool) {
+	visits := make([]float64, m.init.Len())
+	var maxVisits float64
+	for _, t := range m.trees {
+		for i, v := range t.visits() {
+			visits[i] += v
+			maxVisits = math.Max(maxVisits, visits[i])
+		}
+	}
+	if maxVisits == 0 {
+		return -1, false
+	}
+
+	//Divide by the most visits to keep the weights from overflowing
+	for i, v := range visits {
+		visits[i] = math.Pow(v/maxVisits, 1/m.temperature)
+	}
+	return m.trees[0].sample(visits), true
+}
+
+//VisitDistribution returns the fraction of the visits made to each
+//action of the current state by every collected tree, indexed by action.
+//It can be used as a training target for a prior.
+//
+//VisitDistribution returns the same errors as BestAction, and
+//returns a nil distribution if no tree has visited any action.
+func (m *MCTS) VisitDistribution() ([]float64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+
+	distribution := make([]float64, m.init.Len())
+	var total float64
+	for _, t := range m.trees {
+		for i, v := range t.visits() {
+			distribution[i] += v
+			total += v
+		}
+	}
+	if total == 0 {
+		return nil, nil
+	}
+	for i := range distribution {
+		distribution[i] /= total
+	}
+	return distribution, nil
+}
+
+//RootValue returns the expected score of each player from the current
+//state, indexed by Player, averaged over every collected tree weighted
+//by the rounds performed on each tree. It can be used as a training
+//target for an Evaluator.
+//
+//RootValue returns the same errors as BestAction, and
+//returns nil if no tree has performed any rounds.
+func (m *MCTS) RootValue() ([]float64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+
+	var values []float64
+	var totalVisits float64
+	for _, t := range m.trees {
+		treeValues, visits := t.rootValue()
+		for p, v := range treeValues {
+			for int(p) >= len(values) {
+				values = append(values, 0)
+			}
+			values[p] += v * visits
+		}
+		totalVisits += visits
+	}
+	if totalVisits == 0 {
+		return nil, nil
+	}
+	for p := range values {
+		values[p] /= totalVisits
+	}
+	return values, nil
+}
+
+//check returns the error BestAction returns for the trees
+//collected and the current state, if any
+func (m *MCTS) check() error {
+	if len(m.trees) == 0 {
+		return ErrNoTrees
+	} else if m.init.IsTerminal() {
+		return ErrTerminal
+	} else if m.init.Len() <= 0 {
+		return ErrNoActions
+	}
+	return nil
+}
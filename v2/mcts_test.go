@@ -2,6 +2,7 @@ package gmcts
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"testing"
 
@@ -51,6 +52,21 @@ func (g tttGame) Winners() []Player {
 	return []Player{getPlayerID(winner)}
 }
 
+//denseTTTGame is a tttGame that declares its number of players
+type denseTTTGame struct {
+	tttGame
+}
+
+func (g denseTTTGame) ApplyAction(i int) (Game, error) {
+	game, err := g.game.ApplyAction(g.actions[i])
+
+	return denseTTTGame{tttGame{game, game.GetActions()}}, err
+}
+
+func (g denseTTTGame) NumPlayers() int {
+	return 2
+}
+
 //Global vars to be checked by other tests
 var newGame, finishedGame tttGame
 var firstMove tictactoe.Move
@@ -63,44 +79,26 @@ func TestMain(m *testing.M) {
 	newGame = tttGame{game: tictactoe.NewGame()}
 	newGame.actions = newGame.game.GetActions()
 
-	game := newGame
-	concurrentSearches := 1 //runtime.NumCPU()
-
-	var setFirstMove sync.Once
+	//Set the tree to perform benchmarks on
 	var setTestingTree sync.Once
+	config := SearchConfig{
+		Rounds: 10000,
+		SetupTree: func(tree *Tree) {
+			setTestingTree.Do(func() {
+				treeToTest = tree
+			})
+		},
+	}
 
-	for !game.IsTerminal() {
-		mcts := NewMCTS(game)
-
-		var wait sync.WaitGroup
-		wait.Add(concurrentSearches)
-		for i := 0; i < concurrentSearches; i++ {
-			go func() {
-				tree := mcts.SpawnTree()
-				tree.SearchRounds(10000)
-				mcts.AddTree(tree)
-				wait.Done()
-
-				//Set the tree to perform benchmarks on
-				setTestingTree.Do(func() {
-					treeToTest = tree
-				})
-			}()
-		}
-		wait.Wait()
-
-		bestAction, _ := mcts.BestAction()
-		nextState, _ := game.ApplyAction(bestAction)
-		game = nextState.(tttGame)
-		fmt.Println(game.game)
-
-		//Save the first action taken
-		setFirstMove.Do(func() {
-			firstMove = newGame.actions[bestAction]
-		})
+	record, _ := SelfPlay(newGame, []SearchConfig{config, config}, 0)
+	for _, move := range record.Moves[1:] {
+		fmt.Println(move.State.(tttGame).game)
 	}
-	//Save the terminal game state
-	finishedGame = game
+	fmt.Println(record.Final.(tttGame).game)
+
+	//Save the first action taken and the terminal game state
+	firstMove = newGame.actions[record.Moves[0].Action]
+	finishedGame = record.Final.(tttGame)
 
 	m.Run()
 }
@@ -142,6 +140,70 @@ func TestTerminalState(t *testing.T) {
 	}
 }
 
+func TestVisitDistribution(t *testing.T) {
+	mcts := NewMCTS(denseTTTGame{newGame})
+	if _, err := mcts.VisitDistribution(); err != ErrNoTrees {
+		t.Errorf("gmcts: recieved error %v for the visit distribution of no trees: wanted %v", err, ErrNoTrees)
+		t.FailNow()
+	}
+	for i := 0; i < 2; i++ {
+		tree := mcts.SpawnTree()
+		tree.SearchRounds(1000)
+		mcts.AddTree(tree)
+	}
+
+	distribution, _ := mcts.VisitDistribution()
+	var total, mostVisits float64
+	var mostVisited int
+	for i, p := range distribution {
+		total += p
+		if p > mostVisits {
+			mostVisited, mostVisits = i, p
+		}
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("gmcts: visit distribution sums to %f: wanted 1", total)
+		t.FailNow()
+	}
+	if fmt.Sprintf("%v", newGame.actions[mostVisited]) != "{1 1}" {
+		t.Errorf("gmcts: visit distribution favors %v: wanted {1 1}", newGame.actions[mostVisited])
+		t.FailNow()
+	}
+
+	//The first player should be favored, and every game
+	//ends with a total score of 1 between the players
+	value, _ := mcts.RootValue()
+	if len(value) != 2 || value[0] <= value[1] || math.Abs(value[0]+value[1]-1) > 1e-9 {
+		t.Errorf("gmcts: root value is %v: wanted 2 values summing to 1, favoring player 0", value)
+		t.FailNow()
+	}
+}
+
+func TestSeededSearch(t *testing.T) {
+	//Searches with the same seed must be the same. These visits and
+	//scores were recorded from the former recursive search, which
+	//the iterative search must keep matching.
+	wantVisits := []float64{217, 120, 253, 145, 550, 172, 186, 145, 212}
+	wantScores := []float64{139, 65.5, 167, 84, 407, 104.5, 115, 84, 135}
+	for i := 0; i < 2; i++ {
+		tree := NewMCTS(newGame).SpawnTree()
+		tree.SearchRounds(2000)
+
+		root := tree.current
+		for a := range root.children {
+			if visits, score := root.childVisits[a], root.children[a].score(0); visits != wantVisits[a] || score != wantScores[a] {
+				t.Errorf("Seeded tree visited action %d %.0f times with a score of %.1f: wanted %.0f times with a score of %.1f",
+					a, visits, score, wantVisits[a], wantScores[a])
+				t.FailNow()
+			}
+		}
+		if nodes, depth := tree.Nodes(), tree.MaxDepth(); nodes != 1782 || depth != 6 {
+			t.Errorf("Seeded tree has %d nodes and a depth of %d: wanted 1782 nodes and a depth of 6", nodes, depth)
+			t.FailNow()
+		}
+	}
+}
+
 func BenchmarkTicTacToe1KRounds(b *testing.B) {
 	mcts := NewMCTS(newGame)
 	b.ResetTimer()
@@ -152,6 +214,96 @@ func BenchmarkTicTacToe1KRounds(b *testing.B) {
 
 func BenchmarkTicTacToe10KRounds(b *testing.B) {
 	mcts := NewMCTS(newGame)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mcts.SpawnTree().SearchRounds(10000)
+	}
+}
+
+func TestDenseScores(t *testing.T) {
+	//Storing scores in slices should not change the search
+	mapTree := NewMCTS(newGame).SpawnTree()
+	mapTree.SearchRounds(1000)
+	denseTree := NewMCTS(denseTTTGame{newGame}).SpawnTree()
+	denseTree.SearchRounds(1000)
+
+	if mapInfo, denseInfo := mapTree.Info(), denseTree.Info(); mapInfo != denseInfo {
+		t.Errorf("Tree with dense scores searched %+v: wanted %+v", denseInfo, mapInfo)
+		t.FailNow()
+	}
+}
+
+func BenchmarkTicTacToe10KRoundsDense(b *testing.B) {
+	mcts := NewMCTS(denseTTTGame{newGame})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mcts.SpawnTree().SearchRounds(10000)
+	}
+}
+
+//nimGame is a game of nim with a single pile, where players take 1 to 3
+//stones, and the player taking the last stone wins. Its states are cheap,
+//so searching it is dominated by the work of the tree itself.
+type nimGame struct {
+	stones int
+	player Player
+}
+
+func (g nimGame) Len() int {
+	if g.stones < 3 {
+		return g.stones
+	}
+	return 3
+}
+
+func (g nimGame) ApplyAction(i int) (Game, error) {
+	return nimGame{g.stones - i - 1, 1 - g.player}, nil
+}
+
+func (g nimGame) Hash() interface{} {
+	return g
+}
+
+func (g nimGame) Player() Player {
+	return g.player
+}
+
+func (g nimGame) IsTerminal() bool {
+	return g.stones == 0
+}
+
+func (g nimGame) Winners() []Player {
+	return []Player{1 - g.player}
+}
+
+//denseNimGame is a nimGame that declares its number of players
+type denseNimGame struct {
+	nimGame
+}
+
+func (g denseNimGame) ApplyAction(i int) (Game, error) {
+	next, err := g.nimGame.ApplyAction(i)
+	return denseNimGame{next.(nimGame)}, err
+}
+
+func (g denseNimGame) NumPlayers() int {
+	return 2
+}
+
+func BenchmarkNim10KRounds(b *testing.B) {
+	mcts := NewMCTS(nimGame{stones: 30})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mcts.SpawnTree().SearchRounds(10000)
+	}
+}
+
+func BenchmarkNim10KRoundsDense(b *testing.B) {
+	mcts := NewMCTS(denseNimGame{nimGame{stones: 30}})
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		mcts.SpawnTree().SearchRounds(10000)
@@ -165,3 +317,48 @@ func BenchmarkTicTacToe100KRounds(b *testing.B) {
 		mcts.SpawnTree().SearchRounds(100000)
 	}
 }
+
+//lineGame is a game where players take turns extending a line, which
+//is a draw once it reaches its maximum length. With a single action in
+//every state, each round of the search adds a node below the deepest
+//node, so searching this game builds a tree as deep as its rounds.
+type lineGame int
+
+const maxLineLength = 2000
+
+func (g lineGame) Len() int {
+	return 1
+}
+
+func (g lineGame) ApplyAction(i int) (Game, error) {
+	return g + 1, nil
+}
+
+func (g lineGame) Hash() interface{} {
+	return g
+}
+
+func (g lineGame) Player() Player {
+	return Player(g % 2)
+}
+
+func (g lineGame) IsTerminal() bool {
+	return g >= maxLineLength
+}
+
+func (g lineGame) Winners() []Player {
+	return []Player{Player(0), Player(1)}
+}
+
+func (g lineGame) NumPlayers() int {
+	return 2
+}
+
+func BenchmarkDeepTree2KRounds(b *testing.B) {
+	mcts := NewMCTS(lineGame(0))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mcts.SpawnTree().SearchRounds(maxLineLength)
+	}
+}
@@ -1,8 +1,12 @@
 package gmcts
 
 import (
+	"context"
 	"math/rand"
 	"sync"
+	"time"
+
+	"github.com/0xhexnumbers/gmcts/v2/bandit"
 )
 
 //Player is an id for the player
@@ -35,6 +39,42 @@ type Game interface {
 	Winners() []Player
 }
 
+//MutableGame is an optional interface a Game may implement to have its
+//actions made and unmade in place, instead of creating a new state for
+//every action taken.
+//
+//Random playouts make actions in place on the state of the node they start
+//from, and unmake them once the playout is over. States are only cloned when
+//a new node must be stored in the tree. As the stored states are changed
+//during a search, the value returned by Hash must not share memory with
+//the state.
+type MutableGame interface {
+	Game
+
+	//Do applies the ith action (0-indexed) to this state in place,
+	//returning an error for invalid actions
+	Do(i int) error
+
+	//Undo reverts the last action applied by Do
+	Undo()
+
+	//Clone returns a copy of this state that can be changed
+	//independently of it
+	Clone() MutableGame
+}
+
+//PlayerCounter is an optional interface a Game may implement
+//to declare the number of players in the game.
+//
+//If the initial state of an MCTS implements PlayerCounter, players
+//must be numbered from 0 to NumPlayers()-1. This lets the trees index
+//each player's score by the player itself, instead of looking up the
+//slot each player was given when it was first found.
+type PlayerCounter interface {
+	//NumPlayers returns the number of players in the game
+	NumPlayers() int
+}
+
 type gameState struct {
 	Game
 	gameHash
@@ -46,29 +86,127 @@ type gameHash struct {
 	//This is to separate states that seemingly look the same,
 	//but actually occur on different turn orders. Without this,
 	//the directed acyclic graph will become a directed cyclic graph,
-	//which this MCTS implementation cannot handle properly.
+	//which this MCTS implementation only handles in graph mode.
 	turn int
 }
 
+//Repeater is an optional interface a Game may implement to declare
+//the outcome of a repeated state when searching in graph mode.
+//Games that do not implement Repeater treat repetitions as a draw.
+type Repeater interface {
+	//RepetitionWinners returns the list of players that win the
+	//game when this state is repeated
+	RepetitionWinners() []Player
+}
+
+//Equaler is an optional interface a Game may implement to have its
+//states compared when their hashes collide. It is required to search
+//with any collision mode other than CollisionsUnchecked.
+type Equaler interface {
+	//Equal returns true if this state is the same as the given state
+	Equal(Game) bool
+}
+
 //MCTS contains functionality for the MCTS algorithm
 type MCTS struct {
 	init  Game
 	trees []*Tree
 	mutex *sync.RWMutex
 	seed  int64
+	graph bool
+
+	transpositions TranspositionMode
+	collisions     CollisionMode
+
+	//noiseAlpha and noiseFraction configure the Dirichlet noise mixed
+	//into the priors of the roots of the trees spawned, and temperature
+	//is the temperature actions are sampled with by BestAction
+	noiseAlpha    float64
+	noiseFraction float64
+	temperature   float64
 }
 
 type node struct {
 	state gameState
 	tree  *Tree
 
-	children          []*node
+	children []*node
+
+	//unvisitedChildren holds the children left to visit before
+	//any child is selected by the tree's selection policy, which
+	//is empty if the policy scores unvisited children
 	unvisitedChildren []*node
 	childVisits       []float64
 	actionCount       int
 
-	nodeScore  map[Player]float64
+	nodeScore  scores
 	nodeVisits int
+
+	//nodeSquares is the sum of the squared scores of each player,
+	//which is only kept when the tree has a selection policy
+	nodeSquares scores
+
+	//nodeValue is the value of this node backed up from its
+	//children, which is only kept with UCT3 transpositions
+	nodeValue scores
+
+	//onPath is true while this node is on the path being searched
+	onPath bool
+
+	//priors holds the prior probability of each action, which is only
+	//kept when the tree has a prior or the node is a root with noise
+	priors []float64
+}
+
+//scores holds a value for each player, indexed by the player's slot
+//in the tree. Scores grow as values are added for new slots, so that
+//games not declaring their number of players don't need maps.
+type scores []float64
+
+//TranspositionMode is a strategy for handling states reached
+//from multiple parents in a tree.
+type TranspositionMode int
+
+const (
+	//TranspositionsUCT2 shares nodes between parents. Children are
+	//exploited using their own statistics, and explored using the
+	//visits made from each parent. This is the default.
+	TranspositionsUCT2 TranspositionMode = iota
+
+	//TranspositionsUCT3 shares nodes between parents like UCT2, but
+	//children are exploited using a value backed up from their own
+	//children, weighted by the visits made to each of them. This keeps
+	//the value of a shared node consistent across all of its parents.
+	TranspositionsUCT3
+
+	//TranspositionsDisabled never shares nodes, creating a separate
+	//node each time a state is reached.
+	TranspositionsDisabled
+)
+
+//CollisionMode is a strategy for handling distinct states
+//whose hashes collide in a tree's cache of states.
+type CollisionMode int
+
+const (
+	//CollisionsUnchecked trusts that states with the same hash are
+	//the same state, sharing a node between them. This is the default.
+	CollisionsUnchecked CollisionMode = iota
+
+	//CollisionsSeparated compares states with the same hash using their
+	//Equal method, keeping distinct states as separate nodes. Each
+	//collision is reported to the tree's collision handler, if any.
+	CollisionsSeparated
+)
+
+//CollisionError describes two distinct states found with the same hash.
+type CollisionError struct {
+	//Hash is the value returned by both states' Hash method
+	Hash interface{}
+
+	//Cached is the state already in the tree, and
+	//State is the distinct state that collided with it
+	Cached, State Game
 }
 
 //Tree represents a game state tree
@@ -77,4 +215,373 @@ type Tree struct {
 	gameStates       map[gameHash]*node
 	explorationConst float64
 	randSource       *rand.Rand
+
+	//graph shares nodes between states reached on different
+	//turns, detecting repeated states on the searched path
+	graph          bool
+	transpositions TranspositionMode
+
+	//policy selects the children of fully expanded nodes,
+	//or is nil to select them with UCT2
+	policy SelectionPolicy
+
+	//collided holds the nodes of distinct states whose
+	//key is already used by another node in gameStates
+	collisions     CollisionMode
+	collided       map[gameHash][]*node
+	collisionCount int
+
+	//collisionHandler is reported the collisions found in a
+	//round, held in collisionErrors until the round is over
+	collisionHandler func(*CollisionError)
+	collisionErrors  []*CollisionError
+
+	//nodeCount and maxTurn keep track of the size of the tree
+	//as nodes are created. In graph mode, where nodes keep the
+	//turn they were created at, maxPath is the depth of the
+	//deepest node created on a path searched from the root.
+	nodeCount int
+	maxTurn   int
+	maxPath   int
+
+	//players is the number of players in the game, or 0 if the
+	//game does not declare it. Otherwise, seenPlayers holds every
+	//player found while searching, in the order of their slots
+	//in scores.
+	players     int
+	seenPlayers []Player
+
+	//maxRolloutDepth is the most actions a playout may take
+	//before its state is evaluated, or 0 for no limit
+	maxRolloutDepth int
+	evaluator       Evaluator
+	prior           Prior
+
+	//noiseAlpha is the concentration of the Dirichlet noise mixed into
+	//the priors of the root, making up noiseFraction of the priors
+	noiseAlpha    float64
+	noiseFraction float64
+
+	//sequentialHalving splits the rounds of SearchRounds between the
+	//root's actions with sequential halving, and halvingResult holds
+	//the actions remaining after the last sequential halving of the root.
+	//rootChild is the child of the root the round being searched must
+	//select, if any.
+	sequentialHalving bool
+	halvingResult     []int
+	rootChild         int
+
+	//gumbelActions is the number of the root's actions sampled by
+	//Gumbel search, or 0 if it is disabled. gumbel holds the perturbed
+	//logits of the root's actions, and gumbelResult holds the actions
+	//remaining after the last Gumbel search of the root.
+	gumbelActions int
+	gumbel        []float64
+	gumbelResult  []int
+
+	//earlyStop stops SearchRounds once the best action can
+	//no longer change, counting the rounds skipped in savedRounds
+	earlyStop   bool
+	savedRounds int
+
+	//progress is the callback the search progress is reported to
+	progress progress
+
+	observer Observer
+
+	//path holds the nodes searched in the current round
+	path []step
+
+	//mutex guards the nodes of the tree so that a tree
+	//can be searched in the background while it is being
+	//rerooted or queried for its best action.
+	mutex *sync.Mutex
+}
+
+//SelectionPolicy scores the children of a node to select the next child to
+//search. The child with the highest score is selected. Children are only
+//scored once each of them has been visited from the node at least once,
+//except with PUCT, which scores unvisited children by their prior so that
+//children the prior neglects may never be visited.
+//
+//Each child is scored as an arm of a bandit, where its pulls are the visits
+//made to it from the node, and its rewards are the scores of the player to
+//move. Any bandit.Scorer can be used as a selection policy.
+type SelectionPolicy = bandit.Scorer
+
+//UCB1 selects children with the UCB1 formula. See bandit.UCB1.
+type UCB1 = bandit.UCB1
+
+//UCB1Tuned selects children with the UCB1-Tuned formula. See bandit.UCB1Tuned.
+type UCB1Tuned = bandit.UCB1Tuned
+
+//UCBV selects children with the UCB-V formula. See bandit.UCBV.
+type UCBV = bandit.UCBV
+
+//Thompson selects children with Thompson sampling. See bandit.Thompson.
+type Thompson = bandit.Thompson
+
+//PUCT selects children with the PUCT formula, using the prior
+//probability of each action. See bandit.PUCT.
+type PUCT = bandit.PUCT
+
+//Evaluator scores a non-terminal game state reached by a playout
+//that was cut short. The returned slice holds the score of each
+//player, indexed by Player, where a win is worth 1, a loss 0, and
+//a draw 1 divided by the number of players drawing.
+type Evaluator func(Game) []float64
+
+//Prior returns the prior probability of taking each action of a
+//non-terminal game state, indexed by action. The probabilities
+//should sum to 1.
+type Prior func(Game) []float64
+
+//playout is the result of a simulated game
+type playout struct {
+	//winners each have score added to their score
+	winners []Player
+	score   float64
+
+	//scores holds the score of each player, indexed by Player,
+	//when the playout was cut short and evaluated
+	scores []float64
+}
+
+//step is a node searched in a round, along with
+//the index of the child selected from it
+type step struct {
+	node  *node
+	child int
+}
+
+//SearchInfo is a snapshot of the statistics of a tree's root.
+type SearchInfo struct {
+	//BestAction is the index of the action with the highest
+	//win rate, or -1 if the root has not been expanded yet
+	BestAction int
+
+	//Visits is the number of times BestAction has been searched
+	Visits int
+
+	//Value is the win rate of BestAction for the player to move
+	Value float64
+
+	//Rounds is the number of rounds performed on the tree
+	Rounds int
+
+	//Nodes is the number of nodes in the tree
+	Nodes int
+
+	//Depth is the maximum depth of the tree
+	Depth int
+}
+
+//Observer receives events from each phase of a tree's search.
+//
+//Depths are measured in actions from the root of the tree.
+type Observer interface {
+	//Select is called when a child of a node is selected to be searched
+	Select(SelectEvent)
+
+	//Expand is called when a node's children are created
+	Expand(ExpandEvent)
+
+	//Simulate is called when a random playout ends
+	Simulate(SimulateEvent)
+
+	//Backpropagate is called when a node on the searched path is updated
+	Backpropagate(BackpropagateEvent)
+}
+
+//SelectEvent describes the selection of a child.
+type SelectEvent struct {
+	//Depth is the depth of the node the child was selected from
+	Depth int
+
+	//Child is the index of the selected child
+	Child int
+
+	//Unvisited is true if the child was selected because
+	//it had not been visited from this node before
+	Unvisited bool
+}
+
+//ExpandEvent describes the expansion of a node.
+type ExpandEvent struct {
+	//Depth is the depth of the expanded node
+	Depth int
+
+	//Hash is the value returned by the expanded state's Hash method
+	Hash interface{}
+
+	//Children is the number of children created
+	Children int
+}
+
+//SimulateEvent describes a random playout.
+type SimulateEvent struct {
+	//Depth is the depth of the node the playout started from
+	Depth int
+
+	//Length is the number of actions taken during the playout
+	Length int
+
+	//Winners are the winners of the terminal state reached, or the
+	//players drawing if the playout was cut short and counted as a draw.
+	//If the game does not implement PlayerCounter, the draw is between
+	//the players found while searching so far.
+	Winners []Player
+
+	//Scores holds the score of each player, indexed by Player,
+	//if the playout was cut short and scored by an Evaluator
+	Scores []float64
+
+	//Repetition is true if no playout took place, as the
+	//searched path reached a repeated state in graph mode
+	Repetition bool
+}
+
+//BackpropagateEvent describes the update of a node on the searched path.
+type BackpropagateEvent struct {
+	//Depth is the depth of the updated node
+	Depth int
+
+	//Winners are the players whose score was increased
+	Winners []Player
+
+	//Score is the amount each winner's score was increased by
+	Score float64
+
+	//Scores holds the amount each player's score was increased by,
+	//indexed by Player, if the playout was scored by an Evaluator
+	Scores []float64
+}
+
+//progress holds the callback a tree reports its search progress to
+type progress struct {
+	callback func(SearchInfo)
+	rounds   int
+	interval time.Duration
+
+	lastRounds int
+	lastReport time.Time
+}
+
+//Ponderer is a handle to a tree being searched in the background.
+type Ponderer struct {
+	tree   *Tree
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+//Clock describes the state of the game clock of the player
+//about to search for an action.
+type Clock struct {
+	//Remaining is the time left on the player's clock
+	Remaining time.Duration
+
+	//Increment is the time added to the player's clock
+	//after each of their moves
+	Increment time.Duration
+
+	//Move is the number of moves the player has made so far
+	Move int
+}
+
+//TimeManager decides how long a tree should be searched for
+//in games played on a clock.
+type TimeManager struct {
+	//GameLength is the expected number of moves a player
+	//makes in a game
+	GameLength int
+
+	//MinMovesToGo is the least number of moves the remaining
+	//time is expected to be split across
+	MinMovesToGo int
+
+	//Overhead is the time reserved on each move for
+	//communication lag
+	Overhead time.Duration
+
+	//MaxExtension is how many times the allocated budget
+	//a search may be extended to while the best action is unstable
+	MaxExtension float64
+
+	//EarlyStop is the fraction of the allocated budget after
+	//which a search may stop early if the best action is stable
+	EarlyStop float64
+
+	//VisitGap is the fraction of the root's visits the most visited
+	//action must lead the second most visited action by to be
+	//considered stable
+	VisitGap float64
+
+	//StableChecks is the number of consecutive checks the best
+	//action must remain unchanged to be considered stable
+	StableChecks int
+}
+
+//SearchConfig configures the search made for each of a player's moves.
+type SearchConfig struct {
+	//Rounds is the number of rounds each tree is searched for
+	Rounds int
+
+	//Trees is the number of trees searched concurrently.
+	//A value of 0 searches a single tree.
+	Trees int
+
+	//ExplorationConst is the exploration constant of each tree.
+	//A value of 0 uses DefaultExplorationConst.
+	ExplorationConst float64
+
+	//Setup configures the MCTS of a move before its trees are
+	//spawned, such as to set its temperature for the first few moves.
+	//It is given the number of moves made in the game so far.
+	Setup func(m *MCTS, move int)
+
+	//SetupTree configures each tree before it is searched,
+	//such as to set its selection policy or prior
+	SetupTree func(t *Tree)
+}
+
+//GameRecord is the record of a game played by SelfPlay.
+type GameRecord struct {
+	//Moves holds each move made in the game, in order
+	Moves []MoveRecord
+
+	//Final is the terminal state the game ended on, or
+	//the last state reached if SelfPlay returned an error
+	Final Game
+
+	//Winners are the winners of the game's terminal state
+	Winners []Player
+}
+
+//MoveRecord is the record of a move made in a game,
+//along with the statistics of the search that made it.
+type MoveRecord struct {
+	//State is the state the move was made from
+	State Game
+
+	//Player is the player that made the move
+	Player Player
+
+	//Action is the index of the action taken
+	Action int
+
+	//Distribution is the fraction of visits made to each action
+	//by the search, as given by MCTS.VisitDistribution
+	Distribution []float64
+
+	//Value is the expected score of each player found by
+	//the search, as given by MCTS.RootValue
+	Value []float64
+
+	//Rounds and Nodes are the number of rounds performed
+	//and nodes created by every tree of the search
+	Rounds int
+	Nodes  int
+
+	//Duration is the time the search took
+	Duration time.Duration
 }
@@ -0,0 +1,151 @@
+package gmcts
+
+import "testing"
+
+//boardGame is a tic-tac-toe game that makes and unmakes its moves in place
+type boardGame struct {
+	board [9]byte
+	moves []int
+}
+
+var boardLines = [][3]int{
+	{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+	{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+	{0, 4, 8}, {2, 4, 6},
+}
+
+func newBoardGame() *boardGame {
+	return &boardGame{moves: make([]int, 0, 9)}
+}
+
+func (g *boardGame) winner() byte {
+	for _, line := range boardLines {
+		if p := g.board[line[0]]; p != 0 && p == g.board[line[1]] && p == g.board[line[2]] {
+			return p
+		}
+	}
+	return 0
+}
+
+//square returns the index of the ith empty square
+func (g *boardGame) square(i int) int {
+	for s, p := range g.board {
+		if p == 0 {
+			if i == 0 {
+				return s
+			}
+			i--
+		}
+	}
+	return -1
+}
+
+func (g *boardGame) Len() int {
+	return 9 - len(g.moves)
+}
+
+func (g *boardGame) ApplyAction(i int) (Game, error) {
+	next := g.Clone()
+	err := next.Do(i)
+	return next, err
+}
+
+func (g *boardGame) Hash() interface{} {
+	return g.board
+}
+
+func (g *boardGame) Player() Player {
+	return Player(len(g.moves) % 2)
+}
+
+func (g *boardGame) IsTerminal() bool {
+	return len(g.moves) == 9 || g.winner() != 0
+}
+
+func (g *boardGame) Winners() []Player {
+	switch g.winner() {
+	case 'x':
+		return []Player{0}
+	case 'o':
+		return []Player{1}
+	}
+	return []Player{0, 1}
+}
+
+func (g *boardGame) Do(i int) error {
+	piece := byte('x')
+	if g.Player() == 1 {
+		piece = 'o'
+	}
+	s := g.square(i)
+	g.board[s] = piece
+	g.moves = append(g.moves, s)
+	return nil
+}
+
+func (g *boardGame) Undo() {
+	last := len(g.moves) - 1
+	g.board[g.moves[last]] = 0
+	g.moves = g.moves[:last]
+}
+
+func (g *boardGame) Clone() MutableGame {
+	clone := &boardGame{board: g.board, moves: make([]int, len(g.moves), 9)}
+	copy(clone.moves, g.moves)
+	return clone
+}
+
+//immutableBoardGame is a boardGame that does not implement MutableGame,
+//so it is only searched using ApplyAction
+type immutableBoardGame struct {
+	game *boardGame
+}
+
+func (g immutableBoardGame) Len() int          { return g.game.Len() }
+func (g immutableBoardGame) Hash() interface{} { return g.game.Hash() }
+func (g immutableBoardGame) Player() Player    { return g.game.Player() }
+func (g immutableBoardGame) IsTerminal() bool  { return g.game.IsTerminal() }
+func (g immutableBoardGame) Winners() []Player { return g.game.Winners() }
+
+func (g immutableBoardGame) ApplyAction(i int) (Game, error) {
+	next, err := g.game.ApplyAction(i)
+	return immutableBoardGame{next.(*boardGame)}, err
+}
+
+func TestMutableGame(t *testing.T) {
+	//Making actions in place should not change the search
+	mutableMCTS := NewMCTS(newBoardGame())
+	mutableTree := mutableMCTS.SpawnTree()
+	mutableTree.SearchRounds(10000)
+	immutableTree := NewMCTS(immutableBoardGame{newBoardGame()}).SpawnTree()
+	immutableTree.SearchRounds(10000)
+
+	if mutableInfo, immutableInfo := mutableTree.Info(), immutableTree.Info(); mutableInfo != immutableInfo {
+		t.Errorf("Tree with a mutable game searched %+v: wanted %+v", mutableInfo, immutableInfo)
+		t.FailNow()
+	}
+
+	//The initial state should be left untouched
+	if state := mutableMCTS.init.(*boardGame); len(state.moves) != 0 || state.board != [9]byte{} {
+		t.Errorf("Searching a mutable game changed the initial state: %v", state.board)
+		t.FailNow()
+	}
+}
+
+func BenchmarkMutableGame10KRounds(b *testing.B) {
+	mcts := NewMCTS(newBoardGame())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mcts.SpawnTree().SearchRounds(10000)
+	}
+}
+
+func BenchmarkImmutableGame10KRounds(b *testing.B) {
+	mcts := NewMCTS(immutableBoardGame{newBoardGame()})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mcts.SpawnTree().SearchRounds(10000)
+	}
+}
@@ -0,0 +1,57 @@
+package gmcts
+
+import "github.com/0xhexnumbers/gmcts/v2/bandit"
+
+//SetSelectionPolicy sets the policy used to select the children of
+//nodes this tree searches through. A nil policy, which is the default,
+//selects children with UCT2 using the tree's exploration constant.
+//
+//Policies relying on the variance of scores need the squared scores of
+//each node, which are only kept while a policy is set. The policy should
+//therefore be set before the tree is searched.
+func (t *Tree) SetSelectionPolicy(policy SelectionPolicy) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.policy = policy
+}
+
+//policyScore returns the score of the ith child for the given
+//player using the tree's selection policy
+func (n *node) policyScore(i int, p Player) float64 {
+	//Children that were never visited have no statistics,
+	//and are only scored by policies scoring unvisited children
+	child := n.children[i]
+	var mean, variance float64
+	if child.nodeVisits > 0 {
+		mean = child.score(p) / float64(child.nodeVisits)
+		variance = child.nodeSquares.get(n.tree.slot(p))/float64(child.nodeVisits) - mean*mean
+		if variance < 0 || child.nodeSquares == nil {
+			variance = 0
+		}
+		if n.tree.transpositions == TranspositionsUCT3 {
+			mean = child.value(p)
+		}
+	}
+
+	arm := bandit.Arm{
+		Pulls:    n.childVisits[i],
+		Mean:     mean,
+		Variance: variance,
+		Prior:    1 / float64(n.actionCount),
+	}
+	if n.priors != nil {
+		arm.Prior = n.priors[i]
+	}
+	return n.tree.policy.Score(arm, float64(n.nodeVisits), n.tree.randSource)
+}
+
+//scoresUnvisited returns true if the tree's selection policy scores
+//children that have not been visited yet, which PUCT does by their
+//prior, instead of visiting every child of a node before scoring them
+func (t *Tree) scoresUnvisited() bool {
+	switch t.policy.(type) {
+	case PUCT, *PUCT:
+		return true
+	}
+	return false
+}
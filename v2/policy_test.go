@@ -0,0 +1,57 @@
+package gmcts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSelectionPolicies(t *testing.T) {
+	policies := []SelectionPolicy{
+		UCB1{C: math.Sqrt2},
+		UCB1Tuned{},
+		UCBV{Zeta: 1.2, C: 1},
+		Thompson{},
+	}
+	for _, policy := range policies {
+		tree := NewMCTS(newGame).SpawnTree()
+		tree.SetSelectionPolicy(policy)
+		tree.SearchRounds(10000)
+
+		mostVisited, _, _ := tree.current.mostVisited()
+		if fmt.Sprintf("%v", newGame.actions[mostVisited]) != "{1 1}" {
+			t.Errorf("Tree with policy %T visited %v the most: wanted {1 1}", policy, newGame.actions[mostVisited])
+			t.FailNow()
+		}
+	}
+}
+
+func TestPolicyFirstPlayout(t *testing.T) {
+	//Each of the root's 9 children is visited once in the first 9
+	//rounds, and its score must hold the result of that playout
+	tree := NewMCTS(newGame).SpawnTree()
+	tree.SetSelectionPolicy(Thompson{})
+	tree.SearchRounds(9)
+
+	for i, child := range tree.current.children {
+		if total := child.score(0) + child.score(1); child.nodeVisits != 1 || total != 1 {
+			t.Errorf("Child %d has %d visits and a total score of %f: wanted 1 visit and a score of 1", i, child.nodeVisits, total)
+			t.FailNow()
+		}
+	}
+}
+
+func TestSquaredScores(t *testing.T) {
+	tree := NewMCTS(newGame).SpawnTree()
+	tree.SetSelectionPolicy(UCB1Tuned{})
+	tree.SearchRounds(1000)
+
+	//Every score is at most 1, so squares can't exceed the scores
+	root := tree.current
+	player := root.state.Player()
+	squares := root.nodeSquares.get(tree.slot(player))
+	if squares <= 0 || squares > root.score(player) {
+		t.Errorf("Root has squared score %f and score %f: wanted 0 < squared score <= score", squares, root.score(player))
+		t.FailNow()
+	}
+}
@@ -0,0 +1,124 @@
+package gmcts
+
+import "context"
+
+//Ponder starts searching the tree in a background goroutine,
+//such as while the opponent is deciding on their move. The
+//search continues until the returned Ponderer is stopped.
+//
+//While pondering, the tree may be rerooted, added to an MCTS
+//wrapper, or queried for its best action.
+//
+//Ponder will panic under the same conditions as SearchContext.
+func (t *Tree) Ponder() *Ponderer {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Ponderer{
+		tree:   t,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(p.done)
+		t.SearchContext(ctx)
+	}()
+	return p
+}
+
+//Tree returns the tree being searched in the background.
+func (p *Ponderer) Tree() *Tree {
+	return p.tree
+}
+
+//Stop stops the background search and waits for the
+//round currently being searched to finish.
+func (p *Ponderer) Stop() {
+	p.cancel()
+	<-p.done
+}
+
+//Reroot changes the root of the tree to the node matching
+//the given state, keeping the subtree already searched from it.
+//All nodes that cannot be reached from the new root are discarded.
+//
+//Reroot returns false, and leaves the tree unchanged, if the given
+//state has not been reached by this tree yet.
+func (t *Tree) Reroot(state Game) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	//Find the shallowest node matching the given state. Unless
+	//collisions are unchecked, nodes with the same hash are
+	//compared to the state, as they may hold a distinct one.
+	hash := state.Hash()
+	var newRoot *node
+	seen := map[*node]bool{t.current: true}
+	queue := []*node{t.current}
+	for len(queue) > 0 && newRoot == nil {
+		n := queue[0]
+		queue = queue[1:]
+		if n.state.hash == hash && (t.collisions == CollisionsUnchecked || state.(Equaler).Equal(n.state.Game)) {
+			newRoot = n
+		}
+		for _, child := range n.children {
+			if !seen[child] {
+				seen[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	if newRoot == nil {
+		return false
+	}
+
+	//Only keep the nodes reachable from the new root. In graph mode,
+	//the root is kept as well, as it may be reached again.
+	t.gameStates = make(map[gameHash]*node)
+	t.collided = make(map[gameHash][]*node)
+	//In graph mode, the depth of each node is its
+	//shortest distance from the new root
+	nodeCount, maxTurn, maxPath := 0, newRoot.state.turn, 0
+	depths := map[*node]int{newRoot: 0}
+	queue = []*node{newRoot}
+	if t.graph {
+		t.cache(t.key(newRoot.state.gameHash), newRoot)
+		nodeCount++
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, child := range n.children {
+			if _, seen := depths[child]; seen {
+				continue
+			}
+			depths[child] = depths[n] + 1
+			queue = append(queue, child)
+
+			nodeCount++
+			if child.state.turn > maxTurn {
+				maxTurn = child.state.turn
+			}
+			if depths[child] > maxPath {
+				maxPath = depths[child]
+			}
+			if t.transpositions != TranspositionsDisabled {
+				t.cache(t.key(child.state.gameHash), child)
+			}
+		}
+	}
+
+	if newRoot != t.current {
+		t.addNoise(newRoot)
+	}
+	t.current = newRoot
+	t.gumbel, t.gumbelResult = nil, nil
+	t.halvingResult = nil
+	t.nodeCount = nodeCount
+	t.maxTurn = maxTurn
+	t.maxPath = maxPath
+
+	//The new root has fewer visits than the old one, so rounds
+	//between progress reports are counted from its visits
+	t.progress.lastRounds = newRoot.nodeVisits
+	return true
+}
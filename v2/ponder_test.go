@@ -0,0 +1,89 @@
+package gmcts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPonderReroot(t *testing.T) {
+	mcts := NewMCTS(newGame)
+	tree := mcts.SpawnTree()
+	mcts.AddTree(tree)
+
+	ponder := tree.Ponder()
+	time.Sleep(10 * time.Millisecond)
+
+	//Take the middle square while the tree is still pondering
+	nextState, _ := newGame.ApplyAction(4)
+	mcts.Reroot(nextState)
+	if _, err := mcts.BestAction(); err != nil {
+		t.Errorf("gmcts: could not get best action after rerooting: %s", err)
+		t.FailNow()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	ponder.Stop()
+
+	if tree.Rounds() == 0 {
+		t.Errorf("Tree performed 0 rounds while pondering: wanted > 0")
+		t.FailNow()
+	}
+	if depth := tree.MaxDepth(); depth > 8 {
+		t.Errorf("Tree has depth %d after rerooting: wanted <= 8", depth)
+		t.FailNow()
+	}
+}
+
+func TestRerootUnknownState(t *testing.T) {
+	tree := NewMCTS(newGame).SpawnTree()
+	rounds := tree.Rounds()
+	if tree.Reroot(finishedGame) {
+		t.Errorf("Tree rerooted on a state it has never searched")
+		t.FailNow()
+	}
+	if tree.Rounds() != rounds {
+		t.Errorf("Tree changed after failing to reroot")
+		t.FailNow()
+	}
+}
+
+func TestRerootProgress(t *testing.T) {
+	tree := NewMCTS(newGame).SpawnTree()
+
+	reports := 0
+	tree.SetProgress(func(info SearchInfo) {
+		reports++
+	}, 100, 0)
+	tree.SearchRounds(5000)
+
+	//The new root has fewer visits than the old root
+	//had, yet must still be reported every 100 rounds
+	nextState, _ := newGame.ApplyAction(4)
+	if !tree.Reroot(nextState) {
+		t.Errorf("Tree could not reroot on a searched state")
+		t.FailNow()
+	}
+	reports = 0
+	tree.SearchRounds(1000)
+
+	if reports != 10 {
+		t.Errorf("Tree reported its progress %d times after rerooting: wanted 10", reports)
+		t.FailNow()
+	}
+}
+
+func TestPonderAccessors(t *testing.T) {
+	//Querying a tree while it is pondering must not race with the search
+	tree := NewMCTS(newGame).SpawnTree()
+	ponder := tree.Ponder()
+	deadline := time.Now().Add(time.Second)
+	for tree.Rounds() < 1000 && time.Now().Before(deadline) {
+		tree.Nodes()
+		tree.MaxDepth()
+		tree.SavedRounds()
+		tree.Collisions()
+		tree.Info()
+		time.Sleep(10 * time.Microsecond)
+	}
+	ponder.Stop()
+}
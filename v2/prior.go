@@ -0,0 +1,83 @@
+package gmcts
+
+import (
+	"fmt"
+
+	"github.com/0xhexnumbers/gmcts/v2/bandit"
+)
+
+//expandPriors sets the prior probability of each action of this
+//node as it is expanded, mixing noise into the priors of the root
+func (n *node) expandPriors() {
+	if n.tree.prior != nil {
+		n.priors = n.tree.prior(n.state.Game)
+		if len(n.priors) != n.actionCount {
+			panic(fmt.Sprintf("gmcts: Prior returned %d probabilities for a state with %d actions", len(n.priors), n.actionCount))
+		}
+	}
+	if n == n.tree.current {
+		n.tree.addNoise(n)
+	}
+}
+
+//addNoise mixes Dirichlet noise into the priors of the given
+//expanded node if the tree has noise. Nodes without priors
+//are given the same prior for each action before the noise.
+func (t *Tree) addNoise(n *node) {
+	if t.noiseAlpha <= 0 || t.noiseFraction <= 0 || n.actionCount == 0 {
+		return
+	}
+
+	noise := bandit.Dirichlet(t.noiseAlpha, n.actionCount, t.randSource)
+	priors := make([]float64, n.actionCount)
+	for i := range priors {
+		prior := 1 / float64(n.actionCount)
+		if n.priors != nil {
+			prior = n.priors[i]
+		}
+		priors[i] = (1-t.noiseFraction)*prior + t.noiseFraction*noise[i]
+	}
+	n.priors = priors
+}
+
+//visits returns the number of times each of the root's actions were
+//searched, or nil if the root has not been expanded yet
+func (t *Tree) visits() []float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.current.actionCount == 0 {
+		return nil
+	}
+	visits := make([]float64, t.current.actionCount)
+	copy(visits, t.current.childVisits)
+	return visits
+}
+
+//sample returns an index sampled with a probability proportional
+//to its weight, using the tree's random source
+func (t *Tree) sample(weights []float64) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	r := t.randSource.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+
+	//Only reached through rounding errors,
+	//so take the last index with any weight
+	for i := len(weights) - 1; i > 0; i-- {
+		if weights[i] > 0 {
+			return i
+		}
+	}
+	return 0
+}
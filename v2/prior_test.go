@@ -0,0 +1,123 @@
+package gmcts
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDirichletNoise(t *testing.T) {
+	rootPriors := func() []float64 {
+		mcts := NewMCTS(newGame)
+		mcts.SetDirichletNoise(0.3, 0.25)
+		tree := mcts.SpawnTree()
+		tree.SearchRounds(10)
+		return tree.current.priors
+	}
+
+	priors := rootPriors()
+	var total float64
+	for _, p := range priors {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Root priors with noise sum to %f: wanted 1", total)
+		t.FailNow()
+	}
+	if priors[0] == priors[1] {
+		t.Errorf("Root priors with noise are uniform: %v", priors)
+		t.FailNow()
+	}
+	if again := rootPriors(); !reflect.DeepEqual(priors, again) {
+		t.Errorf("Trees with the same seed have root priors %v and %v: wanted the same priors", priors, again)
+		t.FailNow()
+	}
+}
+
+func TestTemperature(t *testing.T) {
+	sampleActions := func(temperature float64) []int {
+		mcts := NewMCTS(newGame)
+		mcts.SetTemperature(temperature)
+		tree := mcts.SpawnTree()
+		tree.SearchRounds(100)
+		mcts.AddTree(tree)
+
+		actions := make([]int, 100)
+		for i := range actions {
+			actions[i], _ = mcts.BestAction()
+		}
+		return actions
+	}
+
+	actions := sampleActions(1)
+	sampled := map[int]bool{}
+	for _, action := range actions {
+		sampled[action] = true
+	}
+	if len(sampled) < 2 {
+		t.Errorf("MCTS with temperature 1 sampled %d distinct actions: wanted >= 2", len(sampled))
+		t.FailNow()
+	}
+	if again := sampleActions(1); !reflect.DeepEqual(actions, again) {
+		t.Errorf("MCTS with the same seed sampled different actions")
+		t.FailNow()
+	}
+
+	//Temperatures close to 0 should always take the most visited action
+	mcts := NewMCTS(newGame)
+	tree := mcts.SpawnTree()
+	tree.SearchRounds(100)
+	mostVisited, _, _ := tree.current.mostVisited()
+	for _, action := range sampleActions(0.01) {
+		if action != mostVisited {
+			t.Errorf("MCTS with temperature 0.01 sampled action %d: wanted most visited action %d", action, mostVisited)
+			t.FailNow()
+		}
+	}
+}
+
+func TestPUCT(t *testing.T) {
+	tree := NewMCTS(newGame).SpawnTree()
+	tree.SetPrior(centerPrior)
+	tree.SetSelectionPolicy(PUCT{C: 1.5})
+	tree.SearchRounds(2000)
+
+	mostVisited, _, _ := tree.current.mostVisited()
+	if fmt.Sprintf("%v", newGame.actions[mostVisited]) != "{1 1}" {
+		t.Errorf("Tree with PUCT visited %v the most: wanted {1 1}", newGame.actions[mostVisited])
+		t.FailNow()
+	}
+}
+
+func TestPUCTUnvisited(t *testing.T) {
+	//With a peaked prior, PUCT should keep searching the favored
+	//action rather than visiting every action of the root first
+	peakedPrior := func(g Game) []float64 {
+		game := g.(tttGame)
+		prior := make([]float64, len(game.actions))
+		for i, action := range game.actions {
+			prior[i] = 0.01
+			if fmt.Sprintf("%v", action) == "{1 1}" {
+				prior[i] = 1 - 0.01*float64(len(prior)-1)
+			}
+		}
+		return prior
+	}
+
+	tree := NewMCTS(newGame).SpawnTree()
+	tree.SetPrior(peakedPrior)
+	tree.SetSelectionPolicy(PUCT{C: 1.5})
+	tree.SearchRounds(32)
+
+	unvisited := 0
+	for _, visits := range tree.current.childVisits {
+		if visits == 0 {
+			unvisited++
+		}
+	}
+	if unvisited == 0 {
+		t.Errorf("Tree with PUCT and a peaked prior visited every action of the root: %v", tree.current.childVisits)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,72 @@
+package gmcts
+
+import "time"
+
+//SetProgress registers a callback that receives a snapshot of the
+//root's statistics while the tree is being searched. The callback is
+//invoked every given number of rounds, or after every given interval
+//of time has passed, whichever comes first. A value of 0 disables
+//that condition, and a nil callback removes any registered callback.
+//
+//The callback is invoked from the goroutine searching the tree, and
+//must not search the tree itself.
+func (t *Tree) SetProgress(callback func(SearchInfo), rounds int, interval time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.progress = progress{
+		callback:   callback,
+		rounds:     rounds,
+		interval:   interval,
+		lastRounds: t.current.nodeVisits,
+		lastReport: time.Now(),
+	}
+}
+
+//Info returns a snapshot of the statistics of the tree's root.
+func (t *Tree) Info() SearchInfo {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.info()
+}
+
+func (t *Tree) info() SearchInfo {
+	root := t.current
+	info := SearchInfo{
+		BestAction: -1,
+		Rounds:     root.nodeVisits,
+		Nodes:      t.nodeCount,
+		Depth:      t.maxDepth(),
+	}
+
+	if root.actionCount > 0 {
+		bestAction := t.chosenAction()
+		if root.childVisits[bestAction] > 0 {
+			info.BestAction = bestAction
+			info.Visits = int(root.childVisits[bestAction])
+			info.Value = root.winRate(bestAction, root.state.Player())
+		}
+	}
+	return info
+}
+
+//report returns a snapshot of the tree if the registered progress
+//callback is due to be invoked. It must be called while holding the lock.
+func (t *Tree) report() (SearchInfo, bool) {
+	p := &t.progress
+	if p.callback == nil {
+		return SearchInfo{}, false
+	}
+
+	due := p.rounds > 0 && t.current.nodeVisits-p.lastRounds >= p.rounds
+	if !due && p.interval > 0 {
+		due = time.Since(p.lastReport) >= p.interval
+	}
+	if !due {
+		return SearchInfo{}, false
+	}
+
+	p.lastRounds = t.current.nodeVisits
+	p.lastReport = time.Now()
+	return t.info(), true
+}
@@ -1,6 +1,7 @@
 package gmcts
 
 import (
+	"context"
 	"fmt"
 	"math"
 )
@@ -10,20 +11,144 @@ const (
 	//Sqrt(2) is a frequent choice for this constant as specified by
 	//https://en.wikipedia.org/wiki/Monte_Carlo_tree_search
 	DefaultExplorationConst = math.Sqrt2
+
+	//rolloutCheckInterval is the number of actions taken in a playout
+	//between checks of whether the search's context is done
+	rolloutCheckInterval = 64
 )
 
 func initializeNode(g gameState, tree *Tree) *node {
 	return &node{
 		state:     g,
 		tree:      tree,
-		nodeScore: make(map[Player]float64),
+		nodeScore: tree.newScores(),
+	}
+}
+
+//newScores returns an empty set of scores with
+//a slot for each player found so far
+func (t *Tree) newScores() scores {
+	if t.players > 0 {
+		return make(scores, t.players)
+	}
+	return make(scores, len(t.seenPlayers))
+}
+
+//slot returns the index of the given player's value in scores. If the
+//number of players is not known, players are given the next free slot
+//the first time they are found, and are then counted in draws. Games
+//have few players, so they are found faster by a scan than by a map.
+func (t *Tree) slot(p Player) int {
+	if t.players > 0 {
+		return int(p)
+	}
+	for i, seen := range t.seenPlayers {
+		if seen == p {
+			return i
+		}
+	}
+	t.seenPlayers = append(t.seenPlayers, p)
+	return len(t.seenPlayers) - 1
+}
+
+//player returns the player whose value is held in the given slot
+func (t *Tree) player(slot int) Player {
+	if t.players > 0 {
+		return Player(slot)
+	}
+	return t.seenPlayers[slot]
+}
+
+//get returns the value in the given slot
+func (s scores) get(slot int) float64 {
+	if slot < len(s) {
+		return s[slot]
+	}
+	return 0
+}
+
+//add adds to the value in the given slot
+func (s *scores) add(slot int, value float64) {
+	s.grow(slot)
+	(*s)[slot] += value
+}
+
+//set sets the value in the given slot
+func (s *scores) set(slot int, value float64) {
+	s.grow(slot)
+	(*s)[slot] = value
+}
+
+//grow adds empty slots up to the given slot
+func (s *scores) grow(slot int) {
+	for len(*s) <= slot {
+		*s = append(*s, 0)
+	}
+}
+
+//score returns the total score of the given player at this node
+func (n *node) score(p Player) float64 {
+	return n.nodeScore.get(n.tree.slot(p))
+}
+
+//addScore adds to the total score of the given player at this node
+func (n *node) addScore(p Player, score float64) {
+	slot := n.tree.slot(p)
+	n.nodeScore.add(slot, score)
+	if n.tree.policy != nil {
+		if n.nodeSquares == nil {
+			n.nodeSquares = n.tree.newScores()
+		}
+		n.nodeSquares.add(slot, score*score)
+	}
+}
+
+//value returns the expected score of the given player at this node.
+//With UCT3 transpositions, this is the value backed up from the node's
+//children. Otherwise, it is the node's average score.
+func (n *node) value(p Player) float64 {
+	if n.nodeValue != nil {
+		return n.nodeValue.get(n.tree.slot(p))
+	}
+	return n.score(p) / float64(n.nodeVisits)
+}
+
+//backupValue recalculates the UCT3 value of this node as the average
+//value of its children, weighted by the visits made to each of them.
+func (n *node) backupValue() {
+	var totalVisits float64
+	for _, visits := range n.childVisits {
+		totalVisits += visits
+	}
+	if totalVisits == 0 {
+		return
+	}
+	if n.nodeValue == nil {
+		n.nodeValue = n.tree.newScores()
+	}
+
+	//Every player with a value in this node's subtree has a score here
+	for slot := range n.nodeScore {
+		p := n.tree.player(slot)
+		var value float64
+		for i, visits := range n.childVisits {
+			if visits > 0 {
+				value += visits * n.children[i].value(p)
+			}
+		}
+		n.nodeValue.set(slot, value/totalVisits)
 	}
 }
 
 //UCT2 algorithm is described in this paper
 //https://www.csse.uwa.edu.au/cig08/Proceedings/papers/8057.pdf
 func (n *node) UCT2(i int, p Player) float64 {
-	exploit := n.children[i].nodeScore[p] / float64(n.children[i].nodeVisits)
+	var exploit float64
+	if n.tree.transpositions == TranspositionsUCT3 {
+		exploit = n.children[i].value(p)
+	} else {
+		exploit = n.children[i].score(p) / float64(n.children[i].nodeVisits)
+	}
 
 	explore := math.Log(float64(n.nodeVisits)) / n.childVisits[i]
 	explore = math.Sqrt(explore)
@@ -31,103 +156,365 @@ func (n *node) UCT2(i int, p Player) float64 {
 	return exploit + n.tree.explorationConst*explore
 }
 
-func (n *node) runSimulation() ([]Player, float64) {
-	var selectedChildIndex int
-	var winners []Player
-	var scoreToAdd float64
-	var terminalState bool
+//runSimulation performs 1 round of the MCTS algorithm from this node.
+//Nodes are selected iteratively down the tree, keeping the searched
+//path so that every node on it can be updated once a game is simulated.
+func (n *node) runSimulation(ctx context.Context) playout {
+	var result playout
 
-	//If we have actions, then there's no need to expand.
-	if n.actionCount == 0 {
-		//If we don't have any actions, then either the state
-		//is terminal, or we haven't expanded the node yet.
-		terminalState = n.state.IsTerminal()
-		if !terminalState {
-			n.expand()
+	path := n.tree.path[:0]
+	for current := n; ; {
+		var selectedChildIndex int
+		var terminalState bool
+		current.onPath = true
+
+		//If we have actions, then there's no need to expand.
+		if current.actionCount == 0 {
+			//If we don't have any actions, then either the state
+			//is terminal, or we haven't expanded the node yet.
+			terminalState = current.state.IsTerminal()
+			if !terminalState {
+				current.expand(len(path))
+				if current.tree.observer != nil {
+					current.tree.observer.Expand(ExpandEvent{len(path), current.state.hash, current.actionCount})
+				}
+			}
 		}
-	}
 
-	if terminalState {
-		//Get the result of the game
-		winners = n.simulate()
-		scoreToAdd = 1.0 / float64(len(winners))
-	} else if len(n.unvisitedChildren) > 0 {
-		//Grab the first unvisited child and run a simulation from that point
-		selectedChildIndex = n.actionCount - len(n.unvisitedChildren)
-		n.children[selectedChildIndex].nodeVisits++
-		n.unvisitedChildren = n.unvisitedChildren[1:]
+		if terminalState {
+			//Get the result of the game
+			result = current.simulate(ctx, len(path))
+			path = append(path, step{current, selectedChildIndex})
+			break
+		} else if len(current.unvisitedChildren) > 0 && (current != n || n.tree.rootChild < 0) {
+			//Grab the first unvisited child and run a simulation from that point
+			selectedChildIndex = current.actionCount - len(current.unvisitedChildren)
+			current.unvisitedChildren = current.unvisitedChildren[1:]
+			result = current.visitFirst(ctx, selectedChildIndex, len(path))
+			path = append(path, step{current, selectedChildIndex})
+			break
+		}
 
-		winners = n.children[selectedChildIndex].simulate()
-		scoreToAdd = 1.0 / float64(len(winners))
-	} else {
-		//Select the child with the max UCT2 score with the current player
-		//and get the results to add from its selection
-		maxScore := -1.0
-		thisPlayer := n.state.Player()
-		for i := 0; i < n.actionCount; i++ {
-			score := n.UCT2(i, thisPlayer)
-			if score > maxScore {
-				maxScore = score
-				selectedChildIndex = i
+		//Select the child with the max score of the tree's selection
+		//policy with the current player, and continue the search from it,
+		//unless the child of the root was chosen for this round
+		if current == n && n.tree.rootChild >= 0 {
+			selectedChildIndex = n.tree.rootChild
+		} else {
+			maxScore := math.Inf(-1)
+			thisPlayer := current.state.Player()
+			for i := 0; i < current.actionCount; i++ {
+				var score float64
+				if current.tree.policy != nil {
+					score = current.policyScore(i, thisPlayer)
+				} else {
+					score = current.UCT2(i, thisPlayer)
+				}
+				if score > maxScore {
+					maxScore = score
+					selectedChildIndex = i
+				}
 			}
 		}
-		winners, scoreToAdd = n.children[selectedChildIndex].runSimulation()
+
+		//Policies scoring unvisited children may select a child that
+		//was never visited, which is simulated from like any other
+		if current.childVisits[selectedChildIndex] == 0 && current.tree.scoresUnvisited() && (current != n || n.tree.rootChild < 0) {
+			result = current.visitFirst(ctx, selectedChildIndex, len(path))
+			path = append(path, step{current, selectedChildIndex})
+			break
+		}
+
+		if current.tree.observer != nil {
+			current.tree.observer.Select(SelectEvent{len(path), selectedChildIndex, false})
+		}
+		path = append(path, step{current, selectedChildIndex})
+		current = current.children[selectedChildIndex]
+		if current.onPath {
+			result = current.repetition(len(path))
+			break
+		}
+	}
+
+	//Update each node in the searched path, starting from the deepest
+	for i := len(path) - 1; i >= 0; i-- {
+		current := path[i].node
+		current.onPath = false
+		current.nodeVisits++
+		if current.actionCount != 0 {
+			current.childVisits[path[i].child]++
+		}
+
+		result.addTo(current)
+		if current.tree.transpositions == TranspositionsUCT3 {
+			current.backupValue()
+		}
+		if current.tree.observer != nil {
+			current.tree.observer.Backpropagate(BackpropagateEvent{i, result.winners, result.score, result.scores})
+		}
+	}
+
+	//Keep the path's memory for the next round
+	n.tree.path = path[:0]
+	return result
+}
+
+//visitFirst runs a simulation from the ith child of this node,
+//which has not been visited from this node yet. The node is the
+//given number of actions away from the root on the searched path.
+func (n *node) visitFirst(ctx context.Context, i, depth int) playout {
+	child := n.children[i]
+	if n.tree.observer != nil {
+		n.tree.observer.Select(SelectEvent{depth, i, true})
 	}
 
-	//Update this node along with each parent in this path recursively
-	n.nodeVisits++
-	if n.actionCount != 0 {
-		n.childVisits[selectedChildIndex]++
+	//A child already on the searched path can only be found in
+	//graph mode, and is a repeated state, which is visited as the
+	//path is updated
+	if child.onPath {
+		return child.repetition(depth + 1)
 	}
+	child.nodeVisits++
+	result := child.simulate(ctx, depth+1)
 
-	for _, p := range winners {
-		n.nodeScore[p] += scoreToAdd
+	//UCT3 values are backed up from each child's own score, and
+	//selection policies score each child's mean and variance, so
+	//the child needs the result of its first playout
+	if n.tree.transpositions == TranspositionsUCT3 || n.tree.policy != nil {
+		result.addTo(child)
 	}
-	return winners, scoreToAdd
+	return result
 }
 
-func (n *node) expand() {
+//expand creates the children of this node, which is the given
+//number of actions away from the root on the searched path
+func (n *node) expand(depth int) {
+	n.tree.slot(n.state.Player())
 	n.actionCount = n.state.Len()
-	n.unvisitedChildren = make([]*node, n.actionCount)
-	n.children = n.unvisitedChildren
+	n.expandPriors()
+	n.children = make([]*node, n.actionCount)
+	if !n.tree.scoresUnvisited() {
+		n.unvisitedChildren = n.children
+	}
 	n.childVisits = make([]float64, n.actionCount)
+	//Nodes are shared between turns in graph mode,
+	//so only the searched path gives their depth
+	if n.tree.graph && depth+1 > n.tree.maxPath {
+		n.tree.maxPath = depth + 1
+	}
+
+	mutable, inPlace := n.state.Game.(MutableGame)
 	for i := 0; i < n.actionCount; i++ {
-		newGame, err := n.state.ApplyAction(i)
+		var newGame Game
+		var err error
+		if inPlace {
+			//Only clone the state now that a node must be stored for it
+			child := mutable.Clone()
+			err = child.Do(i)
+			newGame = child
+		} else {
+			newGame, err = n.state.ApplyAction(i)
+		}
 		if err != nil {
 			panic(fmt.Sprintf("gmcts: Game returned an error when exploring the tree: %s", err))
 		}
 
 		newState := gameState{newGame, gameHash{newGame.Hash(), n.state.turn + 1}}
+		key := n.tree.key(newState.gameHash)
+		shared := n.tree.transpositions != TranspositionsDisabled
 
 		//If we already have a copy in cache, use that and update
 		//this node and its parents
-		if cachedNode, made := n.tree.gameStates[newState.gameHash]; made {
-			n.unvisitedChildren[i] = cachedNode
+		if cachedNode := n.tree.cached(key, newGame); cachedNode != nil && shared {
+			n.children[i] = cachedNode
 		} else {
 			newNode := initializeNode(newState, n.tree)
-			n.unvisitedChildren[i] = newNode
+			n.children[i] = newNode
+			n.tree.nodeCount++
+			if newState.turn > n.tree.maxTurn {
+				n.tree.maxTurn = newState.turn
+			}
 
 			//Save node for reuse
-			n.tree.gameStates[newState.gameHash] = newNode
+			if shared {
+				n.tree.cache(key, newNode)
+			}
 		}
 	}
 }
 
-func (n *node) simulate() []Player {
+//simulate plays random actions from this node until a terminal state
+//is reached, or until the playout is cut short by the maximum rollout
+//depth or by the context being done. The node is the given number of
+//actions away from the root on the searched path.
+//
+//If the node's state is a MutableGame, the actions are made in place
+//on the node's state and unmade once the playout is over.
+func (n *node) simulate(ctx context.Context, depth int) playout {
 	game := n.state.Game
-	for !game.IsTerminal() {
+	mutable, inPlace := game.(MutableGame)
+	done := ctx.Done()
+	maxDepth := n.tree.maxRolloutDepth
+
+	var result playout
+	length := 0
+	for ; ; length++ {
 		var err error
 
+		if game.IsTerminal() {
+			winners := game.Winners()
+			if n.tree.observer != nil {
+				n.tree.observer.Simulate(SimulateEvent{depth, length, winners, nil, false})
+			}
+			result = playout{
+				winners: winners,
+				score:   1.0 / float64(len(winners)),
+			}
+			break
+		}
+
+		cut := maxDepth > 0 && length >= maxDepth
+		if !cut && done != nil && length%rolloutCheckInterval == rolloutCheckInterval-1 {
+			select {
+			case <-done:
+				cut = true
+			default:
+			}
+		}
+		if cut {
+			result = n.evaluate(game, length, depth)
+			break
+		}
+
 		actions := game.Len()
 		if actions <= 0 {
 			panic(fmt.Sprintf("gmcts: game returned no actions on a non-terminal state: %#v", game))
 		}
 
 		randomIndex := n.tree.randSource.Intn(actions)
-		game, err = game.ApplyAction(randomIndex)
+		if inPlace {
+			err = mutable.Do(randomIndex)
+		} else {
+			game, err = game.ApplyAction(randomIndex)
+		}
 		if err != nil {
 			panic(fmt.Sprintf("gmcts: game returned an error while searching the tree: %s", err))
 		}
 	}
-	return game.Winners()
+
+	if inPlace {
+		//The result may share memory with the state, which is about to change
+		result.winners = append([]Player(nil), result.winners...)
+		if result.scores != nil {
+			result.scores = append([]float64(nil), result.scores...)
+		}
+		for i := 0; i < length; i++ {
+			mutable.Undo()
+		}
+	}
+	return result
+}
+
+//evaluate scores a non-terminal state reached by a playout that was cut
+//short. The state is scored by the tree's evaluator if it has one, and
+//is otherwise counted as a draw between every player.
+func (n *node) evaluate(game Game, length, depth int) playout {
+	var result playout
+	if n.tree.evaluator != nil {
+		result.scores = n.tree.evaluator(game)
+	} else {
+		n.tree.slot(game.Player())
+		result = n.tree.draw()
+	}
+
+	if n.tree.observer != nil {
+		n.tree.observer.Simulate(SimulateEvent{depth, length, result.winners, result.scores, false})
+	}
+	return result
+}
+
+//repetition scores a state repeated on the searched path, as given by
+//the game if it implements Repeater, or as a draw otherwise.
+func (n *node) repetition(depth int) playout {
+	var result playout
+	if repeater, ok := n.state.Game.(Repeater); ok {
+		result.winners = repeater.RepetitionWinners()
+		result.score = 1.0 / float64(len(result.winners))
+	} else {
+		n.tree.slot(n.state.Player())
+		result = n.tree.draw()
+	}
+
+	if n.tree.observer != nil {
+		n.tree.observer.Simulate(SimulateEvent{depth, 0, result.winners, nil, true})
+	}
+	return result
+}
+
+//draw returns a playout that is a draw between every player. If the
+//number of players is not known, the draw is between every player
+//found while searching so far, such as to move in an expanded node.
+func (t *Tree) draw() playout {
+	winners := t.seenPlayers
+	if t.players > 0 {
+		winners = make([]Player, t.players)
+		for i := range winners {
+			winners[i] = Player(i)
+		}
+	}
+	if len(winners) == 0 {
+		return playout{}
+	}
+	return playout{
+		winners: append([]Player(nil), winners...),
+		score:   1.0 / float64(len(winners)),
+	}
+}
+
+//addTo adds the scores of this playout to the given node
+func (p playout) addTo(n *node) {
+	if p.scores != nil {
+		for i, score := range p.scores {
+			n.addScore(Player(i), score)
+		}
+		return
+	}
+
+	for _, player := range p.winners {
+		n.addScore(player, p.score)
+	}
+}
+
+//mostVisited returns the index of the most visited child along with
+//the visits of the most and second most visited children.
+func (n *node) mostVisited() (int, float64, float64) {
+	var mostVisited int
+	var first, second float64
+	for i, visits := range n.childVisits {
+		if visits > first {
+			mostVisited = i
+			first, second = visits, first
+		} else if visits > second {
+			second = visits
+		}
+	}
+	return mostVisited, first, second
+}
+
+//bestChild returns the index of the child with the highest
+//win rate for the player to move, along with its win rate.
+func (n *node) bestChild() (int, float64) {
+	//Select the child with the highest winrate
+	var bestAction int
+	bestWinRate := -1.0
+	player := n.state.Player()
+	for i := 0; i < n.actionCount; i++ {
+		winRate := n.winRate(i, player)
+		if winRate > bestWinRate {
+			bestAction = i
+			bestWinRate = winRate
+		}
+	}
+
+	return bestAction, bestWinRate
 }
@@ -0,0 +1,107 @@
+package gmcts
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+//SelfPlay plays a game from the given state until a terminal state is
+//reached, searching each move with the configuration of the player to
+//move, indexed by Player. Each move is made with MCTS.BestAction, which
+//samples the move if the move's MCTS was given a temperature.
+//
+//Games played with the same seed and configurations are the same,
+//as long as the configurations do not search for a set time. Games
+//that may never reach a terminal state should be cut short by the game.
+//
+//SelfPlay returns the record of the game played so far and ErrNoConfig
+//if a player has no configuration, or the error returned by BestAction
+//if it fails. SelfPlay will panic under the same conditions as SearchRounds.
+func SelfPlay(initial Game, configs []SearchConfig, seed int64) (GameRecord, error) {
+	var record GameRecord
+	seeds := rand.New(rand.NewSource(seed))
+
+	state := initial
+	for move := 0; !state.IsTerminal(); move++ {
+		player := state.Player()
+		if int(player) < 0 || int(player) >= len(configs) {
+			record.Final = state
+			return record, ErrNoConfig
+		}
+
+		moveRecord, err := searchMove(state, configs[player], move, seeds.Int63())
+		if err != nil {
+			record.Final = state
+			return record, err
+		}
+		record.Moves = append(record.Moves, moveRecord)
+
+		next, err := state.ApplyAction(moveRecord.Action)
+		if err != nil {
+			record.Final = state
+			return record, err
+		}
+		state = next
+	}
+
+	record.Final = state
+	record.Winners = state.Winners()
+	return record, nil
+}
+
+//searchMove searches the given state with the given
+//configuration, and returns the record of the move made
+func searchMove(state Game, config SearchConfig, move int, seed int64) (MoveRecord, error) {
+	start := time.Now()
+	mcts := NewMCTS(state)
+	mcts.SetSeed(seed)
+	if config.Setup != nil {
+		config.Setup(mcts, move)
+	}
+
+	explorationConst := config.ExplorationConst
+	if explorationConst == 0 {
+		explorationConst = DefaultExplorationConst
+	}
+	trees := make([]*Tree, config.Trees)
+	if len(trees) == 0 {
+		trees = make([]*Tree, 1)
+	}
+
+	//Spawn the trees in order so that each tree's seed is the same every
+	//game, and add them in order once they are all searched
+	var wait sync.WaitGroup
+	for i := range trees {
+		trees[i] = mcts.SpawnCustomTree(explorationConst)
+		if config.SetupTree != nil {
+			config.SetupTree(trees[i])
+		}
+
+		wait.Add(1)
+		go func(t *Tree) {
+			defer wait.Done()
+			t.SearchRounds(config.Rounds)
+		}(trees[i])
+	}
+	wait.Wait()
+
+	record := MoveRecord{
+		State:  state,
+		Player: state.Player(),
+	}
+	for _, t := range trees {
+		mcts.AddTree(t)
+		record.Rounds += t.Rounds()
+		record.Nodes += t.Nodes()
+	}
+
+	var err error
+	if record.Action, err = mcts.BestAction(); err != nil {
+		return record, err
+	}
+	record.Distribution, _ = mcts.VisitDistribution()
+	record.Value, _ = mcts.RootValue()
+	record.Duration = time.Since(start)
+	return record, nil
+}
@@ -0,0 +1,85 @@
+package gmcts
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSelfPlay(t *testing.T) {
+	config := SearchConfig{
+		Rounds: 200,
+		Trees:  2,
+		Setup: func(m *MCTS, move int) {
+			//Sample the opening moves to vary the games
+			if move < 2 {
+				m.SetTemperature(1)
+			}
+		},
+	}
+	play := func(seed int64) []int {
+		record, err := SelfPlay(newGame, []SearchConfig{config, config}, seed)
+		if err != nil {
+			t.Errorf("Self-play returned an error: %s", err)
+			t.FailNow()
+		}
+		if !record.Final.IsTerminal() || !reflect.DeepEqual(record.Winners, record.Final.Winners()) {
+			t.Errorf("Self-play ended on state %v with winners %v", record.Final, record.Winners)
+			t.FailNow()
+		}
+
+		actions := make([]int, len(record.Moves))
+		for i, move := range record.Moves {
+			if move.Player != move.State.Player() || move.Rounds != 400 || len(move.Distribution) != move.State.Len() {
+				t.Errorf("Self-play recorded move %+v: wanted the player to move, 400 rounds, and a distribution of each action", move)
+				t.FailNow()
+			}
+			actions[i] = move.Action
+		}
+		return actions
+	}
+
+	games := map[string]bool{}
+	for seed := int64(0); seed < 4; seed++ {
+		actions := play(seed)
+		if again := play(seed); !reflect.DeepEqual(actions, again) {
+			t.Errorf("Self-play with seed %d played %v and %v: wanted the same game", seed, actions, again)
+			t.FailNow()
+		}
+		games[fmt.Sprint(actions)] = true
+	}
+	if len(games) < 2 {
+		t.Errorf("Self-play with temperature played %d distinct games: wanted >= 2", len(games))
+		t.FailNow()
+	}
+}
+
+func TestSelfPlayNoConfig(t *testing.T) {
+	record, err := SelfPlay(newGame, []SearchConfig{{Rounds: 10}}, 0)
+	if err != ErrNoConfig || len(record.Moves) != 1 {
+		t.Errorf("Self-play with 1 configuration returned %d moves and error %v: wanted 1 move and %v", len(record.Moves), err, ErrNoConfig)
+		t.FailNow()
+	}
+}
+
+//failingGame is a boardGame whose ApplyAction fails, which
+//is only called by SelfPlay as searches make moves in place
+type failingGame struct {
+	*boardGame
+}
+
+var errFailingGame = errors.New("failingGame: ApplyAction failed")
+
+func (g failingGame) ApplyAction(i int) (Game, error) {
+	return nil, errFailingGame
+}
+
+func TestSelfPlayApplyError(t *testing.T) {
+	initial := failingGame{newBoardGame()}
+	record, err := SelfPlay(initial, []SearchConfig{{Rounds: 10}, {Rounds: 10}}, 0)
+	if err != errFailingGame || record.Final != Game(initial) {
+		t.Errorf("Self-play ended on state %v with error %v: wanted the initial state and %v", record.Final, err, errFailingGame)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,138 @@
+package gmcts
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	//checksPerBudget is the number of times a clocked search checks
+	//the stability of the best action during its allocated budget
+	checksPerBudget = 20
+)
+
+//NewTimeManager returns a time manager with defaults suited
+//to games lasting around 40 moves per player.
+func NewTimeManager() TimeManager {
+	return TimeManager{
+		GameLength:   40,
+		MinMovesToGo: 10,
+		Overhead:     50 * time.Millisecond,
+		MaxExtension: 2.5,
+		EarlyStop:    0.3,
+		VisitGap:     0.5,
+		StableChecks: 4,
+	}
+}
+
+//Budget returns the amount of time to search for given
+//the state of the player's clock.
+func (tm TimeManager) Budget(c Clock) time.Duration {
+	available := c.Remaining - tm.Overhead
+	if available <= 0 {
+		return 0
+	}
+
+	movesToGo := tm.GameLength - c.Move
+	if movesToGo < tm.MinMovesToGo {
+		movesToGo = tm.MinMovesToGo
+	}
+	if movesToGo < 1 {
+		movesToGo = 1
+	}
+
+	budget := available/time.Duration(movesToGo) + c.Increment*3/4
+	if budget > available {
+		budget = available
+	}
+	return budget
+}
+
+//MaxBudget returns the most amount of time a search may be
+//extended to when the best action is unstable.
+func (tm TimeManager) MaxBudget(c Clock) time.Duration {
+	available := c.Remaining - tm.Overhead
+	maxBudget := time.Duration(float64(tm.Budget(c)) * tm.MaxExtension)
+
+	//Never spend more than a quarter of the remaining time on one move
+	if maxBudget > available/4 {
+		maxBudget = available / 4
+	}
+	if budget := tm.Budget(c); maxBudget < budget {
+		maxBudget = budget
+	}
+	return maxBudget
+}
+
+//SearchClock searches the tree for the amount of time allocated by the
+//time manager, and returns the amount of time spent searching.
+//
+//The search may stop before the allocated budget if the best action
+//has been stable and the most visited action leads by a wide enough
+//visit gap, or be extended up to MaxBudget if the best action keeps changing.
+//
+//SearchClock will panic if the Game's ApplyAction
+//method returns an error or if any game state's Hash()
+//method returns a noncomparable value.
+func (t *Tree) SearchClock(tm TimeManager, c Clock) time.Duration {
+	start := time.Now()
+	budget := tm.Budget(c)
+	maxBudget := tm.MaxBudget(c)
+
+	//Cut playouts short once the search runs out of time
+	ctx, cancel := context.WithTimeout(context.Background(), maxBudget)
+	defer cancel()
+
+	interval := budget / checksPerBudget
+	nextCheck := interval
+	lastBest := -1
+	stableChecks := 0
+
+	for {
+		elapsed := time.Since(start)
+		if elapsed >= maxBudget {
+			return elapsed
+		}
+
+		if elapsed >= nextCheck {
+			nextCheck = elapsed + interval
+
+			best, gap := t.stability()
+			if best == lastBest {
+				stableChecks++
+			} else {
+				lastBest = best
+				stableChecks = 0
+			}
+			stable := best >= 0 && stableChecks >= tm.StableChecks
+
+			if elapsed >= budget && stable {
+				return elapsed
+			}
+			if float64(elapsed) >= float64(budget)*tm.EarlyStop && stable && gap >= tm.VisitGap {
+				return elapsed
+			}
+		}
+
+		t.search(ctx)
+	}
+}
+
+//stability returns the best action of the tree along with the
+//gap between the two most visited actions as a fraction of the
+//root's visits. The best action is -1 if the root has not
+//been expanded yet.
+func (t *Tree) stability() (int, float64) {
+	t.mutex.Lock()
+	root := t.current
+	if root.actionCount == 0 || len(root.unvisitedChildren) > 0 {
+		t.mutex.Unlock()
+		return -1, 0
+	}
+
+	_, first, second := root.mostVisited()
+	gap := (first - second) / float64(root.nodeVisits)
+	t.mutex.Unlock()
+
+	return t.bestAction(), gap
+}
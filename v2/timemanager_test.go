@@ -0,0 +1,47 @@
+package gmcts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	tm := NewTimeManager()
+	tm.Overhead = 0
+
+	budget := tm.Budget(Clock{Remaining: 40 * time.Second, Move: 0})
+	if budget != time.Second {
+		t.Errorf("Time manager allocated %s: wanted %s", budget, time.Second)
+		t.FailNow()
+	}
+
+	//Late in the game, the time should be split across at least MinMovesToGo moves
+	budget = tm.Budget(Clock{Remaining: 10 * time.Second, Increment: 4 * time.Second, Move: 100})
+	if budget != 4*time.Second {
+		t.Errorf("Time manager allocated %s: wanted %s", budget, 4*time.Second)
+		t.FailNow()
+	}
+
+	if budget := tm.Budget(Clock{}); budget != 0 {
+		t.Errorf("Time manager allocated %s with no time left: wanted 0", budget)
+		t.FailNow()
+	}
+}
+
+func TestSearchClock(t *testing.T) {
+	tm := NewTimeManager()
+	tm.Overhead = 0
+	clock := Clock{Remaining: 2 * time.Second}
+
+	tree := NewMCTS(newGame).SpawnTree()
+	spent := tree.SearchClock(tm, clock)
+
+	if maxBudget := tm.MaxBudget(clock); spent > maxBudget+10*time.Millisecond {
+		t.Errorf("Tree was searched for %s: wanted <= %s", spent, maxBudget)
+		t.FailNow()
+	}
+	if tree.Rounds() == 0 {
+		t.Errorf("Tree performed 0 rounds on the clock: wanted > 0")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,102 @@
+//Package training records the positions of games searched by gmcts
+//as training data for a policy and value network.
+//
+//Each position is recorded with the encoding of its state, the policy
+//target found by the search, such as the visit distribution returned
+//by MCTS.VisitDistribution, and the final outcome of the game it was
+//played in. Records are written as JSON, one record per line, once the
+//outcome of their game is known:
+//
+//	{"state":[0,1,0],"player":0,"policy":[0.2,0.5,0.3],"outcome":[1,0]}
+package training
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+)
+
+//Encoder encodes a game state as the input of a network.
+type Encoder func(gmcts.Game) []float64
+
+//Record is a position of a game, along with its training targets.
+type Record struct {
+	//State is the encoding of the position's state
+	State []float64 `json:"state"`
+
+	//Player is the player to move in the position
+	Player gmcts.Player `json:"player"`
+
+	//Policy is the policy target of the position, indexed by action
+	Policy []float64 `json:"policy"`
+
+	//Outcome is the final score of each player in the game,
+	//indexed by Player, where a win is worth 1, a loss 0, and a
+	//draw 1 divided by the number of players drawing
+	Outcome []float64 `json:"outcome"`
+}
+
+//Writer writes the records of whole games to an io.Writer.
+//Records are kept in memory until the outcome of their game is known.
+type Writer struct {
+	w       *bufio.Writer
+	encode  Encoder
+	players int
+	game    []Record
+}
+
+//NewWriter returns a writer encoding states with the given encoder,
+//for games between the given number of players.
+func NewWriter(w io.Writer, encode Encoder, players int) *Writer {
+	return &Writer{
+		w:       bufio.NewWriter(w),
+		encode:  encode,
+		players: players,
+	}
+}
+
+//Add records a position of the game being played with its policy target.
+func (w *Writer) Add(state gmcts.Game, policy []float64) {
+	w.game = append(w.game, Record{
+		State:  w.encode(state),
+		Player: state.Player(),
+		Policy: policy,
+	})
+}
+
+//EndGame writes every position recorded since the last game ended,
+//with the outcome given by the winners of the game's terminal state.
+func (w *Writer) EndGame(winners []gmcts.Player) error {
+	outcome := make([]float64, w.players)
+	for _, p := range winners {
+		outcome[p] = 1 / float64(len(winners))
+	}
+
+	encoder := json.NewEncoder(w.w)
+	for _, record := range w.game {
+		record.Outcome = outcome
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	w.game = w.game[:0]
+	return w.w.Flush()
+}
+
+//ReadRecords reads every record written by a Writer.
+func ReadRecords(r io.Reader) ([]Record, error) {
+	var records []Record
+	decoder := json.NewDecoder(r)
+	for {
+		var record Record
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,58 @@
+package training
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+	"github.com/0xhexnumbers/gmcts/v2/internal/testgames"
+)
+
+func encodeNim(g gmcts.Game) []float64 {
+	return []float64{float64(g.(testgames.Nim).Stones)}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, encodeNim, 2)
+
+	var game gmcts.Game = testgames.Nim{Stones: 5, MaxTake: 2}
+	for !game.IsTerminal() {
+		mcts := gmcts.NewMCTS(game)
+		tree := mcts.SpawnTree()
+		tree.SearchRounds(100)
+		mcts.AddTree(tree)
+
+		policy, _ := mcts.VisitDistribution()
+		w.Add(game, policy)
+		bestAction, _ := mcts.BestAction()
+		game, _ = game.ApplyAction(bestAction)
+	}
+	if err := w.EndGame(game.Winners()); err != nil {
+		t.Errorf("Could not write game: %s", err)
+		t.FailNow()
+	}
+
+	records, err := ReadRecords(&buf)
+	if err != nil {
+		t.Errorf("Could not read records: %s", err)
+		t.FailNow()
+	}
+
+	//The first player wins from 5 stones by taking 2
+	if len(records) == 0 || !reflect.DeepEqual(records[0].State, []float64{5}) {
+		t.Errorf("Read records %+v: wanted the game to start from 5 stones", records)
+		t.FailNow()
+	}
+	for i, record := range records {
+		if record.Player != gmcts.Player(i%2) || !reflect.DeepEqual(record.Outcome, []float64{1, 0}) {
+			t.Errorf("Read record %+v at move %d: wanted player %d to move and an outcome of [1 0]", record, i, i%2)
+			t.FailNow()
+		}
+		if len(record.Policy) == 0 {
+			t.Errorf("Read record %+v at move %d without a policy", record, i)
+			t.FailNow()
+		}
+	}
+}
@@ -16,7 +16,9 @@ func (t *Tree) Search(duration time.Duration) {
 	t.SearchContext(ctx)
 }
 
-//SearchContext searches the tree using a given context
+//SearchContext searches the tree using a given context.
+//Once the context is done, the playout being simulated is
+//cut short as if it had reached the maximum rollout depth.
 //
 //SearchContext will panic if the Game's ApplyAction
 //method returns an error or if any game state's Hash()
@@ -27,7 +29,7 @@ func (t *Tree) SearchContext(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		default:
-			t.search()
+			t.search(ctx)
 		}
 	}
 }
@@ -37,55 +39,246 @@ func (t *Tree) SearchContext(ctx context.Context) {
 //SearchRounds will panic if the Game's ApplyAction
 //method returns an error or if any game state's Hash()
 //method returns a noncomparable value.
+//
+//If early stopping is enabled, SearchRounds may return before
+//performing every round once the best action is settled.
+//If sequential halving or Gumbel search is enabled, every round is performed.
 func (t *Tree) SearchRounds(rounds int) {
+	t.mutex.Lock()
+	halving, gumbel := t.sequentialHalving, t.gumbelActions > 0
+	t.mutex.Unlock()
+	if gumbel {
+		t.searchGumbel(rounds)
+		return
+	}
+	if halving {
+		t.searchHalving(rounds)
+		return
+	}
+
 	for i := 0; i < rounds; i++ {
-		t.search()
+		if t.settled(rounds - i) {
+			t.mutex.Lock()
+			t.savedRounds += rounds - i
+			t.mutex.Unlock()
+			return
+		}
+		t.search(context.Background())
+	}
+}
+
+//SetEarlyStop enables or disables early stopping for SearchRounds.
+//
+//With early stopping, SearchRounds stops once the most visited action
+//of the root is also its best action, and leads every other action by
+//more visits than there are rounds left to search. Its win rate must
+//also stay above every other action's, even if it loses each round
+//left while another action wins each of them, so that stopping never
+//changes the best action.
+func (t *Tree) SetEarlyStop(enabled bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.earlyStop = enabled
+}
+
+//SavedRounds returns the number of rounds SearchRounds has skipped
+//on this tree because of early stopping.
+func (t *Tree) SavedRounds() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.savedRounds
+}
+
+//settled returns true if early stopping is enabled and no action
+//can overtake the most visited action of the root, either in visits
+//or in win rate, within the given amount of rounds.
+func (t *Tree) settled(roundsLeft int) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	root := t.current
+	if !t.earlyStop || root.actionCount == 0 || len(root.unvisitedChildren) > 0 {
+		return false
+	}
+	left := float64(roundsLeft)
+	mostVisited, first, second := root.mostVisited()
+	if first-second <= left || t.chosenAction() != mostVisited {
+		return false
+	}
+
+	//Each round adds at most 1 visit and a score of at most 1 to
+	//a child, so the leader must keep the best win rate even if it
+	//loses every round left while another action wins every round
+	player := root.state.Player()
+	score, visits := root.winStats(mostVisited, player)
+	worst := score / (visits + left)
+	for i := 0; i < root.actionCount; i++ {
+		if i == mostVisited {
+			continue
+		}
+		score, visits := root.winStats(i, player)
+		if (score+left)/(visits+left) >= worst {
+			return false
+		}
 	}
+	return true
 }
 
-//search performs 1 round of the MCTS algorithm
-func (t *Tree) search() {
-	t.current.runSimulation()
+//search performs 1 round of the MCTS algorithm. Playouts
+//are cut short once the given context is done.
+func (t *Tree) search(ctx context.Context) {
+	t.searchChild(ctx, -1)
+}
+
+//searchChild performs 1 round of the MCTS algorithm, selecting the
+//given child of the root if the root's children have all been visited.
+//A negative child selects the root's child as usual.
+func (t *Tree) searchChild(ctx context.Context, child int) {
+	info, callback, collisions, handler := t.runRound(ctx, child)
+	for _, collision := range collisions {
+		handler(collision)
+	}
+	if callback != nil {
+		callback(info)
+	}
+}
+
+//runRound performs 1 round of the MCTS algorithm while holding the lock.
+//It returns the progress callback due to be invoked with the returned
+//snapshot, or nil if none is due, along with the collisions found in the
+//round and their handler. Both must be invoked once the lock is released.
+func (t *Tree) runRound(ctx context.Context, child int) (SearchInfo, func(SearchInfo), []*CollisionError, func(*CollisionError)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.rootChild = child
+	t.current.runSimulation(ctx)
+	info, due := t.report()
+	var callback func(SearchInfo)
+	if due {
+		callback = t.progress.callback
+	}
+	collisions := t.collisionErrors
+	t.collisionErrors = nil
+	return info, callback, collisions, t.collisionHandler
 }
 
 //Rounds returns the number of MCTS rounds were performed
 //on this tree.
-func (t Tree) Rounds() int {
+func (t *Tree) Rounds() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
 	return t.current.nodeVisits
 }
 
 //Nodes returns the number of nodes created on this tree.
-func (t Tree) Nodes() int {
-	return len(t.gameStates)
+func (t *Tree) Nodes() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.nodeCount
 }
 
 //MaxDepth returns the maximum depth of this tree.
 //The value can be thought of as the amount of moves ahead
 //this tree searched through.
-func (t Tree) MaxDepth() int {
-	maxDepth := 0
-	for _, node := range t.gameStates {
-		if node.state.turn > maxDepth {
-			maxDepth = node.state.turn
-		}
+func (t *Tree) MaxDepth() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.maxDepth()
+}
+
+func (t *Tree) maxDepth() int {
+	if t.graph {
+		return t.maxPath
 	}
-	return maxDepth
+	return t.maxTurn - t.current.state.turn
 }
 
 func (t *Tree) bestAction() int {
-	root := t.current
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.chosenAction()
+}
 
-	//Select the child with the highest winrate
-	var bestAction int
-	bestWinRate := -1.0
-	player := root.state.Player()
-	for i := 0; i < root.actionCount; i++ {
-		winRate := root.children[i].nodeScore[player] / root.childVisits[i]
-		if winRate > bestWinRate {
-			bestAction = i
-			bestWinRate = winRate
-		}
+//chosenAction returns the action the last search of the root chose.
+//It must be called while holding the lock.
+func (t *Tree) chosenAction() int {
+	if len(t.gumbelResult) > 0 {
+		return t.gumbelAction()
 	}
-
+	if len(t.halvingResult) > 0 {
+		return t.halvingAction()
+	}
+	bestAction, _ := t.current.bestChild()
 	return bestAction
 }
+
+//SetObserver sets the observer that receives events from each
+//phase of the tree's search. A nil observer removes the current one.
+//
+//The observer is invoked from the goroutine searching the tree while
+//the tree is locked, and must not call any methods of the tree.
+func (t *Tree) SetObserver(o Observer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.observer = o
+}
+
+//SetMaxRolloutDepth sets the most actions a random playout may take.
+//Playouts reaching this depth are scored by the tree's evaluator, or
+//counted as a draw if the tree has no evaluator. A depth of 0, which
+//is the default, lets playouts run until a terminal state is reached.
+//
+//A playout counted as a draw is split between every player if the
+//game implements PlayerCounter. Otherwise, it is split between the
+//players found while searching so far.
+func (t *Tree) SetMaxRolloutDepth(depth int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.maxRolloutDepth = depth
+}
+
+//SetEvaluator sets the function used to score playouts that were cut
+//short by the maximum rollout depth or by the search's context being
+//done. A nil evaluator counts those playouts as a draw.
+func (t *Tree) SetEvaluator(evaluator Evaluator) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.evaluator = evaluator
+}
+
+//SetPrior sets the function giving the prior probability of each
+//action of a state. A nil prior, which is the default, gives every
+//action the same probability.
+func (t *Tree) SetPrior(prior Prior) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.prior = prior
+}
+
+//key returns the key a state is cached under in gameStates.
+//In graph mode, states are shared across every turn.
+func (t *Tree) key(h gameHash) gameHash {
+	if t.graph {
+		h.turn = 0
+	}
+	return h
+}
+
+//rootValue returns the value of each player at the root,
+//along with the number of times the root was visited
+func (t *Tree) rootValue() (map[Player]float64, float64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	root := t.current
+	values := make(map[Player]float64)
+	if root.nodeVisits == 0 {
+		return values, 0
+	}
+	for slot := range root.nodeScore {
+		p := t.player(slot)
+		values[p] = root.value(p)
+	}
+	return values, float64(root.nodeVisits)
+}
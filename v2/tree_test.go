@@ -1,6 +1,9 @@
 package gmcts
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -51,3 +54,377 @@ func TestSearch(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestEarlyStop(t *testing.T) {
+	mcts := NewMCTS(newGame)
+	tree := mcts.SpawnTree()
+	tree.SetEarlyStop(true)
+	tree.SearchRounds(10000)
+
+	rounds, saved := tree.Rounds(), tree.SavedRounds()
+	if saved <= 0 || rounds+saved != 10000 {
+		t.Errorf("Tree performed %d rounds and saved %d: wanted a total of 10000 with > 0 saved", rounds, saved)
+		t.FailNow()
+	}
+
+	//Stopping early should not change the decision of the tree
+	mcts.AddTree(tree)
+	bestAction, _ := mcts.BestAction()
+	if fmt.Sprintf("%v", newGame.actions[bestAction]) != "{1 1}" {
+		t.Errorf("Tree stopped early with action %v: wanted {1 1}", newGame.actions[bestAction])
+		t.FailNow()
+	}
+}
+
+func TestEarlyStopBestAction(t *testing.T) {
+	//Trees with the same seed must choose the same action
+	//whether or not they stop early
+	var state Game = newGame
+	for _, action := range []int{0, 3, 1} {
+		state, _ = state.ApplyAction(action)
+		for seed := int64(0); seed < 10; seed++ {
+			for _, rounds := range []int{200, 1000} {
+				mcts := NewMCTS(state)
+				mcts.SetSeed(seed)
+				tree := mcts.SpawnTree()
+				tree.SearchRounds(rounds)
+
+				mcts.SetSeed(seed)
+				stopped := mcts.SpawnTree()
+				stopped.SetEarlyStop(true)
+				stopped.SearchRounds(rounds)
+
+				if action, stoppedAction := tree.bestAction(), stopped.bestAction(); action != stoppedAction {
+					t.Errorf("Tree with seed %d chose action %d after %d rounds, and %d after stopping early", seed, action, rounds, stoppedAction)
+					t.FailNow()
+				}
+			}
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	tree := NewMCTS(newGame).SpawnTree()
+
+	var reports []SearchInfo
+	tree.SetProgress(func(info SearchInfo) {
+		reports = append(reports, info)
+	}, 100, 0)
+	tree.SearchRounds(1000)
+
+	if len(reports) != 10 {
+		t.Errorf("Tree reported its progress %d times: wanted 10", len(reports))
+		t.FailNow()
+	}
+	last := reports[len(reports)-1]
+	if last.Rounds != 1000 || last.BestAction < 0 || last.Nodes != tree.Nodes() {
+		t.Errorf("Tree reported %+v after 1000 rounds", last)
+		t.FailNow()
+	}
+}
+
+//countingObserver counts the events received from a tree
+type countingObserver struct {
+	selections, expansions, simulations, rootUpdates int
+}
+
+func (o *countingObserver) Select(SelectEvent) { o.selections++ }
+
+func (o *countingObserver) Expand(ExpandEvent) { o.expansions++ }
+
+func (o *countingObserver) Simulate(SimulateEvent) { o.simulations++ }
+
+func (o *countingObserver) Backpropagate(e BackpropagateEvent) {
+	if e.Depth == 0 {
+		o.rootUpdates++
+	}
+}
+
+func TestObserver(t *testing.T) {
+	tree := NewMCTS(newGame).SpawnTree()
+	observer := new(countingObserver)
+	tree.SetObserver(observer)
+	tree.SearchRounds(1000)
+
+	//Every round runs exactly 1 simulation and updates the root once
+	if observer.simulations != 1000 || observer.rootUpdates != 1000 {
+		t.Errorf("Observer received %d simulations and %d root updates: wanted 1000",
+			observer.simulations, observer.rootUpdates)
+		t.FailNow()
+	}
+	if observer.expansions == 0 || observer.selections < 1000 {
+		t.Errorf("Observer received %d expansions and %d selections", observer.expansions, observer.selections)
+		t.FailNow()
+	}
+}
+
+//endlessGame is a game between 2 players that never ends
+type endlessGame int
+
+func (g endlessGame) Len() int                        { return 2 }
+func (g endlessGame) ApplyAction(i int) (Game, error) { return (g + endlessGame(i) + 1) % 5, nil }
+func (g endlessGame) Hash() interface{}               { return g }
+func (g endlessGame) Player() Player                  { return Player(g % 2) }
+func (g endlessGame) IsTerminal() bool                { return false }
+func (g endlessGame) Winners() []Player               { return nil }
+func (g endlessGame) NumPlayers() int                 { return 2 }
+
+func TestMaxRolloutDepth(t *testing.T) {
+	tree := NewMCTS(endlessGame(0)).SpawnTree()
+	tree.SetMaxRolloutDepth(20)
+	tree.SearchRounds(100)
+
+	//Every playout should be counted as a draw
+	if info := tree.Info(); info.Rounds != 100 || info.Value < 0.4 || info.Value > 0.5 {
+		t.Errorf("Tree searched %+v: wanted 100 rounds of draws", info)
+		t.FailNow()
+	}
+
+	evaluations := 0
+	tree.SetEvaluator(func(g Game) []float64 {
+		evaluations++
+		return []float64{1, 0}
+	})
+	tree.SearchRounds(100)
+	if evaluations != 100 {
+		t.Errorf("Tree evaluated %d playouts: wanted 100", evaluations)
+		t.FailNow()
+	}
+}
+
+//uncountedGame is an endlessGame that does not declare its number of players
+type uncountedGame int
+
+func (g uncountedGame) Len() int { return 2 }
+func (g uncountedGame) ApplyAction(i int) (Game, error) {
+	next, err := endlessGame(g).ApplyAction(i)
+	return uncountedGame(next.(endlessGame)), err
+}
+func (g uncountedGame) Hash() interface{} { return g }
+func (g uncountedGame) Player() Player    { return endlessGame(g).Player() }
+func (g uncountedGame) IsTerminal() bool  { return false }
+func (g uncountedGame) Winners() []Player { return nil }
+
+func TestMaxRolloutDepthUncounted(t *testing.T) {
+	//Cut playouts should be draws between the players found by the search
+	tree := NewMCTS(uncountedGame(0)).SpawnTree()
+	tree.SetMaxRolloutDepth(20)
+	tree.SearchRounds(100)
+	if info := tree.Info(); info.Rounds != 100 || info.Value < 0.4 || info.Value > 0.5 {
+		t.Errorf("Tree searched %+v: wanted 100 rounds of draws", info)
+		t.FailNow()
+	}
+	if players := len(tree.seenPlayers); players != 2 {
+		t.Errorf("Tree found %d players: wanted 2", players)
+		t.FailNow()
+	}
+
+	//No player should score from a draw before any player is found
+	if score := NewMCTS(uncountedGame(0)).SpawnTree().draw().score; score != 0 {
+		t.Errorf("Draw with no players found scored %f: wanted 0", score)
+		t.FailNow()
+	}
+}
+
+func TestSearchEndlessGame(t *testing.T) {
+	//Without a maximum rollout depth, only the context can stop the playouts
+	tree := NewMCTS(endlessGame(0)).SpawnTree()
+	tree.Search(10 * time.Millisecond)
+	if rounds := tree.Rounds(); rounds != 1 {
+		t.Errorf("Tree performed %d rounds: wanted 1", rounds)
+		t.FailNow()
+	}
+}
+
+//repetitionObserver counts the repetitions found by a tree
+type repetitionObserver struct {
+	countingObserver
+	repetitions int
+}
+
+func (o *repetitionObserver) Simulate(e SimulateEvent) {
+	if e.Repetition {
+		o.repetitions++
+	}
+}
+
+//errorGame is an endlessGame whose actions return an error
+type errorGame struct {
+	endlessGame
+}
+
+func (g errorGame) ApplyAction(i int) (Game, error) {
+	return nil, errors.New("errorGame: actions are not allowed")
+}
+
+func TestSearchPanicUnlocks(t *testing.T) {
+	tree := NewMCTS(errorGame{}).SpawnTree()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Tree did not panic on a game returning an error")
+			}
+		}()
+		tree.SearchRounds(1)
+	}()
+
+	//The tree must be unlocked after the panic is recovered
+	done := make(chan struct{})
+	go func() {
+		tree.Rounds()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Tree is still locked after recovering from a panic")
+		t.FailNow()
+	}
+}
+
+func TestGraphMode(t *testing.T) {
+	mcts := NewMCTS(endlessGame(0))
+	mcts.SetGraphMode(true)
+	tree := mcts.SpawnTree()
+	tree.SetMaxRolloutDepth(20)
+	observer := new(repetitionObserver)
+	tree.SetObserver(observer)
+	tree.SearchRounds(1000)
+
+	//Repeating the root must not count as another round
+	if rounds := tree.Rounds(); rounds != 1000 {
+		t.Errorf("Tree performed %d rounds in graph mode: wanted 1000", rounds)
+		t.FailNow()
+	}
+
+	//endlessGame only has 5 distinct states, which should all be shared
+	if nodes := tree.Nodes(); nodes != 5 {
+		t.Errorf("Tree has %d nodes in graph mode: wanted 5", nodes)
+		t.FailNow()
+	}
+	if observer.repetitions == 0 {
+		t.Errorf("Tree found no repetitions in graph mode")
+		t.FailNow()
+	}
+
+	nextState, _ := endlessGame(0).ApplyAction(1)
+	if !tree.Reroot(nextState) || tree.Nodes() != 5 {
+		t.Errorf("Tree could not reroot in graph mode")
+		t.FailNow()
+	}
+}
+
+func TestGraphModeValue(t *testing.T) {
+	mcts := NewMCTS(endlessGame(0))
+	mcts.SetGraphMode(true)
+	tree := mcts.SpawnTree()
+	tree.SetMaxRolloutDepth(20)
+	tree.SearchRounds(1000)
+
+	//Every playout and repetition is a draw, even though
+	//shared nodes are reached from several paths
+	if info := tree.Info(); math.Abs(info.Value-0.5) > 0.01 {
+		t.Errorf("Tree in graph mode has value %f for a game of draws: wanted about 0.5", info.Value)
+		t.FailNow()
+	}
+}
+
+//depthObserver keeps the shallowest depth of the events received from a tree
+type depthObserver struct {
+	minDepth int
+}
+
+func (o *depthObserver) see(depth int) {
+	if depth < o.minDepth {
+		o.minDepth = depth
+	}
+}
+
+func (o *depthObserver) Select(e SelectEvent) { o.see(e.Depth) }
+
+func (o *depthObserver) Expand(e ExpandEvent) { o.see(e.Depth) }
+
+func (o *depthObserver) Simulate(e SimulateEvent) { o.see(e.Depth) }
+
+func (o *depthObserver) Backpropagate(e BackpropagateEvent) { o.see(e.Depth) }
+
+func TestGraphModeDepth(t *testing.T) {
+	mcts := NewMCTS(endlessGame(0))
+	mcts.SetGraphMode(true)
+	tree := mcts.SpawnTree()
+	tree.SetMaxRolloutDepth(20)
+	tree.SearchRounds(1000)
+
+	//Nodes keep the turn they were created at in graph mode, so depths
+	//must still be measured from the new root after rerooting
+	if !tree.Reroot(endlessGame(2)) {
+		t.Errorf("Tree could not reroot in graph mode")
+		t.FailNow()
+	}
+	observer := new(depthObserver)
+	tree.SetObserver(observer)
+	tree.SearchRounds(1000)
+
+	if observer.minDepth < 0 {
+		t.Errorf("Observer received an event at depth %d after rerooting: wanted >= 0", observer.minDepth)
+		t.FailNow()
+	}
+	if depth := tree.MaxDepth(); depth <= 0 {
+		t.Errorf("Tree has depth %d after rerooting in graph mode: wanted > 0", depth)
+		t.FailNow()
+	}
+}
+
+func TestTranspositionModes(t *testing.T) {
+	modes := []TranspositionMode{TranspositionsUCT2, TranspositionsUCT3, TranspositionsDisabled}
+	nodes := make([]int, len(modes))
+	for i, mode := range modes {
+		mcts := NewMCTS(newGame)
+		mcts.SetTranspositionMode(mode)
+		tree := mcts.SpawnTree()
+		tree.SearchRounds(10000)
+		mcts.AddTree(tree)
+
+		bestAction, _ := mcts.BestAction()
+		if fmt.Sprintf("%v", newGame.actions[bestAction]) != "{1 1}" {
+			t.Errorf("Tree with transposition mode %d picked %v: wanted {1 1}", mode, newGame.actions[bestAction])
+			t.FailNow()
+		}
+		nodes[i] = tree.Nodes()
+	}
+
+	//Without transpositions, every state reached creates a new node
+	if nodes[2] <= nodes[0] {
+		t.Errorf("Tree without transpositions has %d nodes: wanted > %d", nodes[2], nodes[0])
+		t.FailNow()
+	}
+}
+
+func TestUCT3Values(t *testing.T) {
+	rootValue := func(mode TranspositionMode) ([]float64, *Tree) {
+		mcts := NewMCTS(denseTTTGame{newGame})
+		mcts.SetTranspositionMode(mode)
+		tree := mcts.SpawnTree()
+		tree.SearchRounds(200)
+		mcts.AddTree(tree)
+		value, _ := mcts.RootValue()
+		return value, tree
+	}
+
+	uct2Value, _ := rootValue(TranspositionsUCT2)
+	uct3Value, tree := rootValue(TranspositionsUCT3)
+	for p := range uct2Value {
+		if uct3Value[p] == 0 || math.Abs(uct3Value[p]-uct2Value[p]) > 0.1 {
+			t.Errorf("Tree with UCT3 transpositions has root value %v: wanted close to UCT2's %v", uct3Value, uct2Value)
+			t.FailNow()
+		}
+	}
+
+	//Every child of the root should have a value backed up from its playouts
+	for i, child := range tree.current.children {
+		if value := child.value(0); value == 0 {
+			t.Errorf("Root child %d has UCT3 value 0 after %.0f visits: wanted > 0", i, tree.current.childVisits[i])
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package zobrist
+
+import (
+	"fmt"
+	"sync"
+)
+
+//Detector catches collisions between keys by remembering the
+//state each key was first computed for.
+//
+//Detectors are meant for testing a game's keys, as every state checked
+//is kept in memory. They can be checked from a game's Hash method while
+//trees are searched in parallel:
+//
+//	func (g *game) Hash() interface{} {
+//		if err := detector.Check(g.key, g.board); err != nil {
+//			panic(err)
+//		}
+//		return g.key
+//	}
+type Detector struct {
+	states     map[Key]interface{}
+	collisions int
+	mutex      sync.Mutex
+}
+
+//Collision is the error returned when two distinct
+//states are found to have the same key.
+type Collision struct {
+	Key Key
+
+	//First is the state the key was first computed for,
+	//and Second is the distinct state that collided with it
+	First, Second interface{}
+}
+
+func (c *Collision) Error() string {
+	return fmt.Sprintf("zobrist: states %v and %v collide on key %016x%016x", c.First, c.Second, c.Key.Hi, c.Key.Lo)
+}
+
+//NewDetector returns a detector that has not checked any states yet.
+func NewDetector() *Detector {
+	return &Detector{states: make(map[Key]interface{})}
+}
+
+//Check records the state the given key was computed for, and returns
+//a *Collision if a distinct state was recorded with the same key before.
+//
+//The state may be any comparable value that tells states apart, such as
+//an array of the board's squares along with the player to move. Check
+//will panic if the state is noncomparable.
+func (d *Detector) Check(k Key, state interface{}) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	first, ok := d.states[k]
+	if !ok {
+		d.states[k] = state
+		return nil
+	}
+	if first == state {
+		return nil
+	}
+
+	d.collisions++
+	return &Collision{Key: k, First: first, Second: state}
+}
+
+//Collisions returns the number of collisions found so far.
+func (d *Detector) Collisions() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.collisions
+}
+
+//Reset forgets every state checked so far.
+func (d *Detector) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.states = make(map[Key]interface{})
+	d.collisions = 0
+}
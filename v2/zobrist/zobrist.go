@@ -0,0 +1,148 @@
+//Package zobrist manages Zobrist keys for hashing board game states.
+//
+//A Zobrist key is the exclusive or of a random key for each feature of
+//a state, such as which piece is on which square and which player is
+//to move. As toggling a feature twice cancels it out, keys can be updated
+//incrementally as pieces are placed, moved, and removed, instead of
+//hashing the whole board on every action.
+//
+//Keys are comparable, so they can be returned directly by a game's Hash method:
+//
+//	func (g *game) Hash() interface{} {
+//		return g.key
+//	}
+//
+//Tables generate either 64-bit or 128-bit keys. 128-bit keys make
+//collisions between distinct states unlikely enough to ignore in most
+//searches, while collisions of 64-bit keys can be caught with a Detector,
+//or handled by the trees searching the game with a gmcts.CollisionMode.
+package zobrist
+
+import (
+	"math/rand"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+)
+
+//Key is a Zobrist key. Keys generated by 64-bit tables
+//only use Lo, leaving Hi as 0.
+type Key struct {
+	Hi, Lo uint64
+}
+
+//Xor returns the exclusive or of two keys.
+func (k Key) Xor(other Key) Key {
+	return Key{Hi: k.Hi ^ other.Hi, Lo: k.Lo ^ other.Lo}
+}
+
+//Table holds the random keys of every feature of a game's states.
+type Table struct {
+	pieces  int
+	squares int
+
+	//keys holds the key of each piece on each square,
+	//indexed by piece*squares + square
+	keys []Key
+
+	//sides holds the key of each player to move
+	sides []Key
+}
+
+//New64 returns a table of 64-bit keys for the given number of piece
+//types, squares, and players. Tables created with the same seed
+//generate the same keys.
+func New64(pieces, squares, players int, seed int64) *Table {
+	return newTable(pieces, squares, players, seed, false)
+}
+
+//New128 returns a table of 128-bit keys for the given number of piece
+//types, squares, and players. Tables created with the same seed
+//generate the same keys.
+func New128(pieces, squares, players int, seed int64) *Table {
+	return newTable(pieces, squares, players, seed, true)
+}
+
+func newTable(pieces, squares, players int, seed int64, wide bool) *Table {
+	if pieces <= 0 || squares <= 0 || players <= 0 {
+		panic("zobrist: tables need at least 1 piece, square, and player")
+	}
+
+	randSource := rand.New(rand.NewSource(seed))
+	randomKey := func() Key {
+		k := Key{Lo: randSource.Uint64()}
+		if wide {
+			k.Hi = randSource.Uint64()
+		}
+		return k
+	}
+
+	t := &Table{
+		pieces:  pieces,
+		squares: squares,
+		keys:    make([]Key, pieces*squares),
+		sides:   make([]Key, players),
+	}
+	for i := range t.keys {
+		t.keys[i] = randomKey()
+	}
+	for i := range t.sides {
+		t.sides[i] = randomKey()
+	}
+	return t
+}
+
+//Piece returns the key of the given piece being on the given square.
+//
+//Piece will panic if the piece or square is out of range of the table.
+func (t *Table) Piece(piece, square int) Key {
+	if piece < 0 || piece >= t.pieces || square < 0 || square >= t.squares {
+		panic("zobrist: piece or square out of range of the table")
+	}
+	return t.keys[piece*t.squares+square]
+}
+
+//Side returns the key of the given player being the player to move.
+//
+//Side will panic if the player is out of range of the table.
+func (t *Table) Side(player gmcts.Player) Key {
+	if player < 0 || int(player) >= len(t.sides) {
+		panic("zobrist: player out of range of the table")
+	}
+	return t.sides[player]
+}
+
+//Toggle places the given piece on the given square of the key's
+//state if it is not there, or removes it if it is.
+func (t *Table) Toggle(k Key, piece, square int) Key {
+	return k.Xor(t.Piece(piece, square))
+}
+
+//Move moves the given piece from one square to another.
+func (t *Table) Move(k Key, piece, from, to int) Key {
+	return k.Xor(t.Piece(piece, from)).Xor(t.Piece(piece, to))
+}
+
+//Pass changes the player to move from one player to another.
+func (t *Table) Pass(k Key, from, to gmcts.Player) Key {
+	return k.Xor(t.Side(from)).Xor(t.Side(to))
+}
+
+//Board returns the key of a whole state from scratch, given the piece
+//on each square, where a negative piece is an empty square, and the
+//player to move. It is useful for the initial state of a game, or for
+//checking that keys updated incrementally are correct.
+//
+//Board will panic if the board holds more squares than the table.
+func (t *Table) Board(board []int, player gmcts.Player) Key {
+	if len(board) > t.squares {
+		panic("zobrist: board holds more squares than the table")
+	}
+
+	k := t.Side(player)
+	for square, piece := range board {
+		if piece >= 0 {
+			k = t.Toggle(k, piece, square)
+		}
+	}
+	return k
+}
@@ -0,0 +1,183 @@
+package zobrist
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xhexnumbers/gmcts/v2"
+	"github.com/0xhexnumbers/gmcts/v2/gamecheck"
+)
+
+var table = New64(2, 9, 2, 0)
+
+//zobristGame is a tic-tac-toe game hashed with incremental Zobrist keys
+type zobristGame struct {
+	board [9]int
+	turn  int
+	key   Key
+}
+
+func newZobristGame() zobristGame {
+	g := zobristGame{}
+	for i := range g.board {
+		g.board[i] = -1
+	}
+	g.key = table.Board(g.board[:], 0)
+	return g
+}
+
+func (g zobristGame) winner() int {
+	lines := [][3]int{
+		{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+		{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+		{0, 4, 8}, {2, 4, 6},
+	}
+	for _, line := range lines {
+		if p := g.board[line[0]]; p >= 0 && p == g.board[line[1]] && p == g.board[line[2]] {
+			return p
+		}
+	}
+	return -1
+}
+
+func (g zobristGame) Len() int {
+	return 9 - g.turn
+}
+
+func (g zobristGame) ApplyAction(i int) (gmcts.Game, error) {
+	for s, p := range g.board {
+		if p >= 0 {
+			continue
+		}
+		if i > 0 {
+			i--
+			continue
+		}
+
+		player := g.Player()
+		g.board[s] = int(player)
+		g.key = table.Toggle(g.key, int(player), s)
+		g.key = table.Pass(g.key, player, 1-player)
+		g.turn++
+		return g, nil
+	}
+	return nil, errors.New("invalid action")
+}
+
+func (g zobristGame) Hash() interface{} {
+	return g.key
+}
+
+func (g zobristGame) Player() gmcts.Player {
+	return gmcts.Player(g.turn % 2)
+}
+
+func (g zobristGame) IsTerminal() bool {
+	return g.turn == 9 || g.winner() >= 0
+}
+
+func (g zobristGame) Winners() []gmcts.Player {
+	if w := g.winner(); w >= 0 {
+		return []gmcts.Player{gmcts.Player(w)}
+	}
+	return []gmcts.Player{0, 1}
+}
+
+func TestIncrementalKeys(t *testing.T) {
+	var game gmcts.Game = newZobristGame()
+	for _, action := range []int{4, 0, 6, 2} {
+		game, _ = game.ApplyAction(action)
+
+		g := game.(zobristGame)
+		if want := table.Board(g.board[:], g.Player()); g.key != want {
+			t.Errorf("Incremental key %v differs from key %v computed from scratch", g.key, want)
+			t.FailNow()
+		}
+		if g.key.Hi != 0 {
+			t.Errorf("64-bit table generated a key with %x in its high bits: wanted 0", g.key.Hi)
+			t.FailNow()
+		}
+	}
+
+	if table.Move(table.Toggle(Key{}, 0, 1), 0, 1, 2) != table.Toggle(Key{}, 0, 2) {
+		t.Errorf("Moving a piece gave a different key than placing it on its destination")
+		t.FailNow()
+	}
+}
+
+func TestTables(t *testing.T) {
+	a, b := New128(2, 9, 2, 1), New128(2, 9, 2, 1)
+	if a.Piece(1, 8) != b.Piece(1, 8) {
+		t.Errorf("Tables created with the same seed generated different keys")
+		t.FailNow()
+	}
+	if a.Piece(1, 8).Hi == 0 {
+		t.Errorf("128-bit table generated a key with no high bits")
+		t.FailNow()
+	}
+}
+
+func TestZobristGame(t *testing.T) {
+	gamecheck.Test(t, newZobristGame())
+
+	detector := NewDetector()
+	seen := map[[9]int]bool{}
+	var walk func(g zobristGame)
+	walk = func(g zobristGame) {
+		if seen[g.board] {
+			return
+		}
+		seen[g.board] = true
+		if err := detector.Check(g.key, g.board); err != nil {
+			t.Errorf("Found collision in tic-tac-toe keys: %s", err)
+			t.FailNow()
+		}
+		if g.IsTerminal() {
+			return
+		}
+		for i := 0; i < g.Len(); i++ {
+			next, _ := g.ApplyAction(i)
+			walk(next.(zobristGame))
+		}
+	}
+	walk(newZobristGame())
+
+	mcts := gmcts.NewMCTS(newZobristGame())
+	tree := mcts.SpawnTree()
+	tree.SearchRounds(10000)
+	mcts.AddTree(tree)
+	if _, err := mcts.BestAction(); err != nil {
+		t.Errorf("Could not search game hashed with Zobrist keys: %s", err)
+		t.FailNow()
+	}
+}
+
+func TestDetector(t *testing.T) {
+	detector := NewDetector()
+	k := Key{Lo: 1}
+	if err := detector.Check(k, "a"); err != nil {
+		t.Errorf("Detector reported a collision on the first state checked: %s", err)
+		t.FailNow()
+	}
+	if err := detector.Check(k, "a"); err != nil {
+		t.Errorf("Detector reported a collision on the same state: %s", err)
+		t.FailNow()
+	}
+
+	err := detector.Check(k, "b")
+	var collision *Collision
+	if !errors.As(err, &collision) || collision.First != "a" || collision.Second != "b" {
+		t.Errorf("Detector returned %v for colliding states: wanted collision between a and b", err)
+		t.FailNow()
+	}
+	if detector.Collisions() != 1 {
+		t.Errorf("Detector counted %d collisions: wanted 1", detector.Collisions())
+		t.FailNow()
+	}
+
+	detector.Reset()
+	if err := detector.Check(k, "b"); err != nil || detector.Collisions() != 0 {
+		t.Errorf("Detector remembered states after being reset")
+		t.FailNow()
+	}
+}
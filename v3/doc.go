@@ -0,0 +1,20 @@
+//Package gmcts is a generic implementation of the
+//Monte-Carlo Tree Search (mcts) algorithm.
+//
+//This version of the package uses type parameters for the game state
+//and its hash, so the states returned by the package never need to be
+//type asserted. Hashes are constrained to be comparable, but this only
+//rejects hashes that cannot be cached at compile time under the go1.18
+//and go1.19 language rules. From go1.20 on, interface types such as any
+//satisfy comparable, and a hash holding a noncomparable value still
+//panics during a search.
+//
+//This package attempts to save memory and time by caching states as to not
+//have duplicate nodes in the search tree. This optimization is efficient for
+//games like tic-tac-toe, checkers, and go among others.
+//
+//This package also allows support for tree parallelization. Trees may
+//be spawned and ran in their own goroutine. After searching, they may be
+//compiled together to produce a more informed action than just searching
+//through one tree.
+package gmcts
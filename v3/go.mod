@@ -0,0 +1,5 @@
+module github.com/0xhexnumbers/gmcts/v3
+
+go 1.18
+
+require github.com/0xhexnumbers/go-tic-tac-toe v0.2.2
@@ -0,0 +1,90 @@
+package gmcts
+
+import (
+	"math/rand"
+	"sync"
+)
+
+//Player is an id for the player
+type Player int
+
+//Game is the interface that represents game states of type G,
+//whose hashes are of type H.
+//
+//Any implementation of Game should be immutable
+//(state cannot change as this package calls any function).
+type Game[G any, H comparable] interface {
+	//Len returns the number of actions to consider.
+	Len() int
+
+	//ApplyAction applies the ith action (0-indexed) to the game state,
+	//and returns a new game state and an error for invalid actions
+	ApplyAction(i int) (G, error)
+
+	//Hash returns a unique representation of the state.
+	Hash() H
+
+	//Player returns the player that can take the next action
+	Player() Player
+
+	//IsTerminal returns true if this game state is a terminal state
+	IsTerminal() bool
+
+	//Winners returns a list of players that have won the game if
+	//IsTerminal() returns true
+	Winners() []Player
+}
+
+type gameState[G Game[G, H], H comparable] struct {
+	game G
+	gameHash[H]
+}
+
+type gameHash[H comparable] struct {
+	hash H
+
+	//This is to separate states that seemingly look the same,
+	//but actually occur on different turn orders. Without this,
+	//the directed acyclic graph will become a directed cyclic graph,
+	//which this MCTS implementation cannot handle properly.
+	turn int
+}
+
+//MCTS contains functionality for the MCTS algorithm
+type MCTS[G Game[G, H], H comparable] struct {
+	init  G
+	trees []*Tree[G, H]
+	mutex *sync.RWMutex
+	seed  int64
+}
+
+type node[G Game[G, H], H comparable] struct {
+	state gameState[G, H]
+	tree  *Tree[G, H]
+
+	children          []*node[G, H]
+	unvisitedChildren []*node[G, H]
+	childVisits       []float64
+	actionCount       int
+
+	nodeScore  map[Player]float64
+	nodeVisits int
+}
+
+//Tree represents a game state tree
+type Tree[G Game[G, H], H comparable] struct {
+	current          *node[G, H]
+	gameStates       map[gameHash[H]]*node[G, H]
+	explorationConst float64
+	randSource       *rand.Rand
+
+	//path holds the nodes searched in the current round
+	path []step[G, H]
+}
+
+//step is a node searched in a round, along with
+//the index of the child selected from it
+type step[G Game[G, H], H comparable] struct {
+	node  *node[G, H]
+	child int
+}
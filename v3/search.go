@@ -0,0 +1,151 @@
+package gmcts
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	//DefaultExplorationConst is the default exploration constant of UCB1 Formula
+	//Sqrt(2) is a frequent choice for this constant as specified by
+	//https://en.wikipedia.org/wiki/Monte_Carlo_tree_search
+	DefaultExplorationConst = math.Sqrt2
+)
+
+func initializeNode[G Game[G, H], H comparable](g gameState[G, H], tree *Tree[G, H]) *node[G, H] {
+	return &node[G, H]{
+		state:     g,
+		tree:      tree,
+		nodeScore: make(map[Player]float64),
+	}
+}
+
+//UCT2 algorithm is described in this paper
+//https://www.csse.uwa.edu.au/cig08/Proceedings/papers/8057.pdf
+func (n *node[G, H]) UCT2(i int, p Player) float64 {
+	exploit := n.children[i].nodeScore[p] / float64(n.children[i].nodeVisits)
+
+	explore := math.Log(float64(n.nodeVisits)) / n.childVisits[i]
+	explore = math.Sqrt(explore)
+
+	return exploit + n.tree.explorationConst*explore
+}
+
+//runSimulation performs 1 round of the MCTS algorithm from this node.
+//Nodes are selected iteratively down the tree, keeping the searched
+//path so that every node on it can be updated once a game is simulated.
+func (n *node[G, H]) runSimulation() ([]Player, float64) {
+	var winners []Player
+	var scoreToAdd float64
+
+	path := n.tree.path[:0]
+	for current := n; ; {
+		var selectedChildIndex int
+		var terminalState bool
+
+		//If we have actions, then there's no need to expand.
+		if current.actionCount == 0 {
+			//If we don't have any actions, then either the state
+			//is terminal, or we haven't expanded the node yet.
+			terminalState = current.state.game.IsTerminal()
+			if !terminalState {
+				current.expand()
+			}
+		}
+
+		if terminalState {
+			//Get the result of the game
+			winners = current.simulate()
+			scoreToAdd = 1.0 / float64(len(winners))
+			path = append(path, step[G, H]{current, selectedChildIndex})
+			break
+		} else if len(current.unvisitedChildren) > 0 {
+			//Grab the first unvisited child and run a simulation from that point
+			selectedChildIndex = current.actionCount - len(current.unvisitedChildren)
+			current.children[selectedChildIndex].nodeVisits++
+			current.unvisitedChildren = current.unvisitedChildren[1:]
+
+			winners = current.children[selectedChildIndex].simulate()
+			scoreToAdd = 1.0 / float64(len(winners))
+			path = append(path, step[G, H]{current, selectedChildIndex})
+			break
+		}
+
+		//Select the child with the max UCT2 score with the current player
+		//and continue the search from it
+		maxScore := -1.0
+		thisPlayer := current.state.game.Player()
+		for i := 0; i < current.actionCount; i++ {
+			score := current.UCT2(i, thisPlayer)
+			if score > maxScore {
+				maxScore = score
+				selectedChildIndex = i
+			}
+		}
+		path = append(path, step[G, H]{current, selectedChildIndex})
+		current = current.children[selectedChildIndex]
+	}
+
+	//Update each node in the searched path, starting from the deepest
+	for i := len(path) - 1; i >= 0; i-- {
+		current := path[i].node
+		current.nodeVisits++
+		if current.actionCount != 0 {
+			current.childVisits[path[i].child]++
+		}
+
+		for _, p := range winners {
+			current.nodeScore[p] += scoreToAdd
+		}
+	}
+
+	//Keep the path's memory for the next round
+	n.tree.path = path[:0]
+	return winners, scoreToAdd
+}
+
+func (n *node[G, H]) expand() {
+	n.actionCount = n.state.game.Len()
+	n.unvisitedChildren = make([]*node[G, H], n.actionCount)
+	n.children = n.unvisitedChildren
+	n.childVisits = make([]float64, n.actionCount)
+	for i := 0; i < n.actionCount; i++ {
+		newGame, err := n.state.game.ApplyAction(i)
+		if err != nil {
+			panic(fmt.Sprintf("gmcts: Game returned an error when exploring the tree: %s", err))
+		}
+
+		newState := gameState[G, H]{newGame, gameHash[H]{newGame.Hash(), n.state.turn + 1}}
+
+		//If we already have a copy in cache, use that and update
+		//this node and its parents
+		if cachedNode, made := n.tree.gameStates[newState.gameHash]; made {
+			n.unvisitedChildren[i] = cachedNode
+		} else {
+			newNode := initializeNode(newState, n.tree)
+			n.unvisitedChildren[i] = newNode
+
+			//Save node for reuse
+			n.tree.gameStates[newState.gameHash] = newNode
+		}
+	}
+}
+
+func (n *node[G, H]) simulate() []Player {
+	game := n.state.game
+	for !game.IsTerminal() {
+		var err error
+
+		actions := game.Len()
+		if actions <= 0 {
+			panic(fmt.Sprintf("gmcts: game returned no actions on a non-terminal state: %#v", game))
+		}
+
+		randomIndex := n.tree.randSource.Intn(actions)
+		game, err = game.ApplyAction(randomIndex)
+		if err != nil {
+			panic(fmt.Sprintf("gmcts: game returned an error while searching the tree: %s", err))
+		}
+	}
+	return game.Winners()
+}